@@ -0,0 +1,82 @@
+package gokick
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// ensureAppAccessToken obtains an app access token before req is sent, in AutoAppAccessToken mode,
+// when the client has no user access token and the app token is missing or about to expire.
+func (c *Client) ensureAppAccessToken(req *http.Request) error {
+	if !c.canRenewAppToken(req.Context()) || req.Context().Value(retryKey) != nil || isTokenEndpoint(req.URL.Path) {
+		return nil
+	}
+
+	c.mu.Lock()
+	token := c.options.AppAccessToken
+	expiresAt := c.appTokenExpiresAt
+	c.mu.Unlock()
+
+	if token != "" && (expiresAt.IsZero() || time.Until(expiresAt) > c.options.TokenRefreshMargin) {
+		return nil
+	}
+
+	err := c.renewAppToken(context.WithValue(req.Context(), retryKey, true), token)
+	if err != nil && (token == "" || !time.Now().Before(expiresAt)) {
+		return err
+	}
+
+	return nil
+}
+
+// renewAppToken fetches a new app access token once for all concurrent callers, unless staleToken
+// has already been replaced.
+func (c *Client) renewAppToken(ctx context.Context, staleToken string) error {
+	result := c.refreshGroup.DoChan("app", func() (interface{}, error) {
+		c.mu.Lock()
+		currentToken := c.options.AppAccessToken
+		c.mu.Unlock()
+
+		if currentToken != staleToken {
+			return nil, nil
+		}
+
+		response, err := c.GetAppAccessToken(context.WithoutCancel(ctx))
+		ContextTrace(ctx).tokenRefreshed("app", err)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get app access token: %w", err)
+		}
+
+		c.mu.Lock()
+		c.options.AppAccessToken = response.AccessToken
+		c.appTokenExpiresAt = time.Time{}
+		if response.ExpiresIn > 0 {
+			c.appTokenExpiresAt = time.Now().Add(time.Duration(response.ExpiresIn) * time.Second)
+		}
+		c.mu.Unlock()
+
+		return nil, nil
+	})
+
+	select {
+	case r := <-result:
+		return r.Err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// canRenewAppToken reports whether requests made with ctx use an app access token managed by the client.
+func (c *Client) canRenewAppToken(ctx context.Context) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	kind, _ := c.selectToken(ctx)
+
+	return c.options.AutoAppAccessToken &&
+		kind == tokenKindApp &&
+		c.options.ClientID != "" &&
+		c.options.ClientSecret != ""
+}
@@ -0,0 +1,108 @@
+package gokick_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/scorfly/gokick"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// appTokenHandler issues numbered app tokens valid for expiresIn seconds. API calls succeed only with the
+// latest issued token.
+func appTokenHandler(expiresIn int, issued *atomic.Int32) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/oauth/token" {
+			if r.FormValue("grant_type") != "client_credentials" {
+				w.WriteHeader(http.StatusBadRequest)
+				fmt.Fprint(w, `{"error":"unsupported_grant_type"}`)
+				return
+			}
+			fmt.Fprintf(w, `{"access_token":"app-%d","expires_in":%d,"token_type":"Bearer"}`, issued.Add(1), expiresIn)
+			return
+		}
+
+		if r.Header.Get("Authorization") != fmt.Sprintf("Bearer app-%d", issued.Load()) {
+			w.WriteHeader(http.StatusUnauthorized)
+			fmt.Fprint(w, `{"message":"unauthorized"}`)
+			return
+		}
+
+		fmt.Fprint(w, `{"data":[]}`)
+	}
+}
+
+func newAutoAppTokenClient(t *testing.T, handler http.HandlerFunc) *gokick.Client {
+	t.Helper()
+
+	return setupMockClientWithOptions(t, gokick.ClientOptions{
+		ClientID:           "client-id",
+		ClientSecret:       "client-secret",
+		AutoAppAccessToken: true,
+	}, handler)
+}
+
+func TestAutoAppAccessToken(t *testing.T) {
+	t.Run("token is obtained once", func(t *testing.T) {
+		var issued atomic.Int32
+		kickClient := newAutoAppTokenClient(t, appTokenHandler(3600, &issued))
+
+		var wg sync.WaitGroup
+		for range 20 {
+			wg.Go(func() {
+				_, err := kickClient.GetChannels(context.Background(), gokick.NewChannelListFilter())
+				assert.NoError(t, err)
+			})
+		}
+		wg.Wait()
+
+		assert.Equal(t, int32(1), issued.Load())
+	})
+
+	t.Run("expiring token is renewed", func(t *testing.T) {
+		var issued atomic.Int32
+		kickClient := newAutoAppTokenClient(t, appTokenHandler(30, &issued))
+
+		for range 2 {
+			_, err := kickClient.GetChannels(context.Background(), gokick.NewChannelListFilter())
+			require.NoError(t, err)
+		}
+
+		assert.Equal(t, int32(2), issued.Load())
+	})
+
+	t.Run("rejected token is renewed", func(t *testing.T) {
+		var issued atomic.Int32
+		kickClient := newAutoAppTokenClient(t, appTokenHandler(3600, &issued))
+		kickClient.SetAppAccessToken("revoked")
+
+		_, err := kickClient.GetChannels(context.Background(), gokick.NewChannelListFilter())
+		require.NoError(t, err)
+		assert.Equal(t, int32(1), issued.Load())
+	})
+
+	t.Run("user token takes precedence", func(t *testing.T) {
+		var issued atomic.Int32
+		kickClient := newAutoAppTokenClient(t, appTokenHandler(3600, &issued))
+		kickClient.SetUserAccessToken("user-access")
+
+		_, err := kickClient.GetChannels(context.Background(), gokick.NewChannelListFilter())
+		require.EqualError(t, err, "Error 401: unauthorized")
+		assert.Equal(t, int32(0), issued.Load())
+	})
+
+	t.Run("token request error", func(t *testing.T) {
+		kickClient := newAutoAppTokenClient(t, func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusUnauthorized)
+			fmt.Fprint(w, `{"error":"invalid_client"}`)
+		})
+
+		_, err := kickClient.GetChannels(context.Background(), gokick.NewChannelListFilter())
+		require.EqualError(t, err, "failed to make request: failed to get app access token: Error 401: invalid_client")
+	})
+}
@@ -0,0 +1,330 @@
+package gokick
+
+import (
+	"bytes"
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const defaultLRUCacheCapacity = 1000
+
+// ErrCacheMiss is returned by CacheBackend.Get when the key is absent or expired.
+var ErrCacheMiss = errors.New("gokick: cache miss")
+
+// DefaultCacheTTLs are the endpoints cached by a ResponseCache unless overridden by ResponseCacheOptions.TTLs.
+var DefaultCacheTTLs = map[string]time.Duration{
+	"/public/v2/categories": time.Hour,
+	"/public/v1/users":      5 * time.Minute,
+	"/public/v1/channels":   time.Minute,
+	"/public/v1/public-key": 24 * time.Hour,
+}
+
+// CacheBackend stores the responses of a ResponseCache. Implementations must be safe for concurrent use.
+type CacheBackend interface {
+	// Get returns the value stored for key, or ErrCacheMiss.
+	Get(ctx context.Context, key string) ([]byte, error)
+	// Set stores value for key, for ttl.
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+}
+
+// ResponseCacheOptions configures a ResponseCache.
+type ResponseCacheOptions struct {
+	// Backend stores the responses. Defaults to an LRUCache of 1000 entries.
+	Backend CacheBackend
+	// TTLs sets how long the responses of an endpoint path (e.g. "/public/v1/users") are kept, overriding
+	// DefaultCacheTTLs. A zero TTL disables caching of the endpoint.
+	TTLs map[string]time.Duration
+}
+
+// ResponseCache caches the successful GET responses of read-mostly endpoints, for the client(s) it is set on
+// through ClientOptions.Cache.
+//
+// Responses are keyed by endpoint, query string and access token, so clients acting for different users never
+// share entries. A Cache-Control header with no-store or no-cache prevents caching, and its max-age replaces the
+// endpoint TTL. A successful POST, PATCH or DELETE on an endpoint invalidates its cached responses, e.g.
+// UpdateStreamCategory invalidates GetChannels. Invalidation is local to the ResponseCache: clients of other
+// processes sharing the backend keep serving their entries until they expire.
+type ResponseCache struct {
+	backend     CacheBackend
+	ttls        map[string]time.Duration
+	mu          sync.Mutex
+	generations map[string]uint64
+	now         func() time.Time
+}
+
+type cachedResponse struct {
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header"`
+	Body       []byte      `json:"body"`
+	StoredAt   time.Time   `json:"stored_at"`
+}
+
+func NewResponseCache(options ResponseCacheOptions) *ResponseCache {
+	backend := options.Backend
+	if backend == nil {
+		backend = NewLRUCache(defaultLRUCacheCapacity)
+	}
+
+	ttls := make(map[string]time.Duration, len(DefaultCacheTTLs)+len(options.TTLs))
+	for endpoint, ttl := range DefaultCacheTTLs {
+		ttls[endpoint] = ttl
+	}
+	for endpoint, ttl := range options.TTLs {
+		ttls[endpoint] = ttl
+	}
+
+	return &ResponseCache{
+		backend:     backend,
+		ttls:        ttls,
+		generations: make(map[string]uint64),
+		now:         time.Now,
+	}
+}
+
+// Invalidate drops the cached responses of an endpoint path, e.g. "/public/v1/channels" when a webhook reports
+// that a channel was updated outside of this client.
+func (rc *ResponseCache) Invalidate(endpoint string) {
+	rc.mu.Lock()
+	rc.generations[Route(endpoint)]++
+	rc.mu.Unlock()
+}
+
+// roundTrip serves req from the cache when possible, and otherwise sends it through next.
+func (rc *ResponseCache) roundTrip(c *Client, next http.RoundTripper, req *http.Request) (*http.Response, error) {
+	endpoint := Route(req.URL.Path)
+
+	if req.Method != http.MethodGet {
+		response, err := next.RoundTrip(req)
+		if err == nil && response.StatusCode < http.StatusMultipleChoices {
+			rc.Invalidate(endpoint)
+		}
+
+		return response, err
+	}
+
+	ttl := rc.ttls[endpoint]
+	if ttl <= 0 {
+		return next.RoundTrip(req)
+	}
+
+	key := rc.key(c, req, endpoint)
+
+	cached, ok := rc.load(req.Context(), key)
+	if ok {
+		return cached.response(req, rc.now()), nil
+	}
+
+	response, err := next.RoundTrip(req)
+	if err != nil || response.StatusCode != http.StatusOK {
+		return response, err
+	}
+
+	ttl, ok = cacheControlTTL(response.Header, ttl)
+	if !ok {
+		return response, nil
+	}
+
+	body, err := io.ReadAll(response.Body)
+	response.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+	response.Body = io.NopCloser(bytes.NewReader(body))
+
+	rc.store(req.Context(), key, cachedResponse{
+		StatusCode: response.StatusCode,
+		Header:     response.Header.Clone(),
+		Body:       body,
+		StoredAt:   rc.now(),
+	}, ttl)
+
+	return response, nil
+}
+
+// key identifies the response of req: endpoint generation, access token and query string.
+func (rc *ResponseCache) key(c *Client, req *http.Request, endpoint string) string {
+	rc.mu.Lock()
+	generation := rc.generations[endpoint]
+	rc.mu.Unlock()
+
+	c.mu.Lock()
+	kind, token := c.selectToken(req.Context())
+	c.mu.Unlock()
+
+	// App access tokens all grant the same view of the API, so their responses are shared across renewals.
+	if kind == tokenKindApp {
+		token = "app"
+	} else if token != "" {
+		hash := sha256.Sum256([]byte(token))
+		token = hex.EncodeToString(hash[:8])
+	}
+
+	return fmt.Sprintf("gokick:%s:%d:%s:%s", endpoint, generation, token, req.URL.Query().Encode())
+}
+
+// load returns the cached response of key. Backend errors are treated as misses.
+func (rc *ResponseCache) load(ctx context.Context, key string) (cachedResponse, bool) {
+	value, err := rc.backend.Get(ctx, key)
+	if err != nil {
+		return cachedResponse{}, false
+	}
+
+	var cached cachedResponse
+	err = json.Unmarshal(value, &cached)
+	if err != nil {
+		return cachedResponse{}, false
+	}
+
+	return cached, true
+}
+
+// store saves a response. The cache is best effort: backend errors are ignored.
+func (rc *ResponseCache) store(ctx context.Context, key string, cached cachedResponse, ttl time.Duration) {
+	value, err := json.Marshal(cached)
+	if err != nil {
+		return
+	}
+
+	_ = rc.backend.Set(ctx, key, value, ttl)
+}
+
+// response rebuilds the cached response, with an Age header telling how long it has been cached.
+func (r cachedResponse) response(req *http.Request, now time.Time) *http.Response {
+	header := r.Header.Clone()
+	if header == nil {
+		header = http.Header{}
+	}
+	header.Set("Age", strconv.Itoa(int(now.Sub(r.StoredAt).Seconds())))
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", r.StatusCode, http.StatusText(r.StatusCode)),
+		StatusCode:    r.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(r.Body)),
+		ContentLength: int64(len(r.Body)),
+		Request:       req,
+	}
+}
+
+// cacheControlTTL applies the Cache-Control header of a response to the endpoint TTL. It returns false when the
+// response must not be cached.
+func cacheControlTTL(header http.Header, ttl time.Duration) (time.Duration, bool) {
+	for _, directive := range strings.Split(header.Get("Cache-Control"), ",") {
+		name, value, _ := strings.Cut(strings.TrimSpace(directive), "=")
+
+		switch strings.ToLower(name) {
+		case "no-store", "no-cache":
+			return 0, false
+		case "max-age":
+			seconds, err := strconv.Atoi(strings.Trim(value, `"`))
+			if err != nil {
+				continue
+			}
+			if seconds <= 0 {
+				return 0, false
+			}
+			ttl = time.Duration(seconds) * time.Second
+		}
+	}
+
+	return ttl, true
+}
+
+// LRUCache is an in-memory CacheBackend evicting the least recently used entries beyond its capacity.
+type LRUCache struct {
+	mu       sync.Mutex
+	capacity int
+	entries  map[string]*list.Element
+	order    *list.List
+	now      func() time.Time
+}
+
+type lruEntry struct {
+	key       string
+	value     []byte
+	expiresAt time.Time
+}
+
+// NewLRUCache returns an LRUCache holding up to capacity entries, or 1000 when capacity is not positive.
+func NewLRUCache(capacity int) *LRUCache {
+	if capacity <= 0 {
+		capacity = defaultLRUCacheCapacity
+	}
+
+	return &LRUCache{
+		capacity: capacity,
+		entries:  make(map[string]*list.Element),
+		order:    list.New(),
+		now:      time.Now,
+	}
+}
+
+func (l *LRUCache) Get(_ context.Context, key string) ([]byte, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	element, ok := l.entries[key]
+	if !ok {
+		return nil, ErrCacheMiss
+	}
+
+	entry := element.Value.(*lruEntry)
+	if !l.now().Before(entry.expiresAt) {
+		l.order.Remove(element)
+		delete(l.entries, key)
+		return nil, ErrCacheMiss
+	}
+
+	l.order.MoveToFront(element)
+
+	return entry.value, nil
+}
+
+func (l *LRUCache) Set(_ context.Context, key string, value []byte, ttl time.Duration) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	expiresAt := l.now().Add(ttl)
+
+	element, ok := l.entries[key]
+	if ok {
+		entry := element.Value.(*lruEntry)
+		entry.value = value
+		entry.expiresAt = expiresAt
+		l.order.MoveToFront(element)
+
+		return nil
+	}
+
+	l.entries[key] = l.order.PushFront(&lruEntry{key: key, value: value, expiresAt: expiresAt})
+
+	for l.order.Len() > l.capacity {
+		oldest := l.order.Back()
+		l.order.Remove(oldest)
+		delete(l.entries, oldest.Value.(*lruEntry).key)
+	}
+
+	return nil
+}
+
+// Len returns the number of entries in the cache, expired ones included until they are evicted.
+func (l *LRUCache) Len() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.order.Len()
+}
@@ -0,0 +1,249 @@
+package gokick_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/scorfly/gokick"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupCacheClient(t *testing.T, cache *gokick.ResponseCache, handler http.HandlerFunc) (*gokick.Client, *atomic.Int32) {
+	t.Helper()
+
+	var calls atomic.Int32
+	options := gokick.ClientOptions{UserAccessToken: "access-token", Cache: cache}
+	kickClient := setupMockClientWithOptions(t, options, func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		handler(w, r)
+	})
+
+	return kickClient, &calls
+}
+
+func usersHandler(w http.ResponseWriter, r *http.Request) {
+	fmt.Fprintf(w, `{"message":"success", "data":[{"name":"user %s", "user_id": 117}]}`, r.URL.Query().Get("id"))
+}
+
+func TestResponseCacheHit(t *testing.T) {
+	kickClient, calls := setupCacheClient(t, gokick.NewResponseCache(gokick.ResponseCacheOptions{}), usersHandler)
+
+	first, err := kickClient.GetUsers(context.Background(), gokick.NewUserListFilter().SetID(117))
+	require.NoError(t, err)
+
+	second, err := kickClient.GetUsers(context.Background(), gokick.NewUserListFilter().SetID(117))
+	require.NoError(t, err)
+
+	assert.Equal(t, int32(1), calls.Load())
+	assert.Equal(t, first.Result, second.Result)
+	assert.Equal(t, "user 117", second.Result[0].Name)
+	assert.Equal(t, http.StatusOK, second.Meta.StatusCode)
+	assert.Equal(t, "0", second.Meta.Header.Get("Age"))
+
+	other, err := kickClient.GetUsers(context.Background(), gokick.NewUserListFilter().SetID(118))
+	require.NoError(t, err)
+	assert.Equal(t, "user 118", other.Result[0].Name)
+	assert.Equal(t, int32(2), calls.Load())
+}
+
+func TestResponseCacheTTL(t *testing.T) {
+	t.Run("expired", func(t *testing.T) {
+		now := time.Now()
+		cache := gokick.NewResponseCache(gokick.ResponseCacheOptions{
+			TTLs: map[string]time.Duration{"/public/v1/users": time.Minute},
+		})
+		gokick.SetResponseCacheNow(cache, func() time.Time { return now })
+		kickClient, calls := setupCacheClient(t, cache, usersHandler)
+
+		_, err := kickClient.GetUsers(context.Background(), gokick.NewUserListFilter())
+		require.NoError(t, err)
+		now = now.Add(time.Minute)
+		_, err = kickClient.GetUsers(context.Background(), gokick.NewUserListFilter())
+		require.NoError(t, err)
+
+		assert.Equal(t, int32(2), calls.Load())
+	})
+
+	t.Run("disabled endpoint", func(t *testing.T) {
+		cache := gokick.NewResponseCache(gokick.ResponseCacheOptions{
+			TTLs: map[string]time.Duration{"/public/v1/users": 0},
+		})
+		kickClient, calls := setupCacheClient(t, cache, usersHandler)
+
+		for range 2 {
+			_, err := kickClient.GetUsers(context.Background(), gokick.NewUserListFilter())
+			require.NoError(t, err)
+		}
+
+		assert.Equal(t, int32(2), calls.Load())
+	})
+
+	t.Run("uncached endpoint", func(t *testing.T) {
+		kickClient, calls := setupCacheClient(t, gokick.NewResponseCache(gokick.ResponseCacheOptions{}),
+			func(w http.ResponseWriter, _ *http.Request) {
+				fmt.Fprint(w, `{"message":"success", "data":[]}`)
+			})
+
+		for range 2 {
+			_, err := kickClient.GetLivestreams(context.Background(), gokick.NewLivestreamListFilter())
+			require.NoError(t, err)
+		}
+
+		assert.Equal(t, int32(2), calls.Load())
+	})
+}
+
+func TestResponseCacheControl(t *testing.T) {
+	testCases := map[string]struct {
+		cacheControl  string
+		wait          time.Duration
+		expectedCalls int32
+	}{
+		"no-store":          {cacheControl: "no-store", expectedCalls: 2},
+		"no-cache":          {cacheControl: "private, no-cache", expectedCalls: 2},
+		"max-age zero":      {cacheControl: "max-age=0", expectedCalls: 2},
+		"max-age":           {cacheControl: "public, max-age=60", expectedCalls: 1},
+		"max-age below TTL": {cacheControl: "max-age=1", wait: time.Second, expectedCalls: 2},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			now := time.Now()
+			cache := gokick.NewResponseCache(gokick.ResponseCacheOptions{})
+			gokick.SetResponseCacheNow(cache, func() time.Time { return now })
+			kickClient, calls := setupCacheClient(t, cache, func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Cache-Control", tc.cacheControl)
+				usersHandler(w, r)
+			})
+
+			_, err := kickClient.GetUsers(context.Background(), gokick.NewUserListFilter())
+			require.NoError(t, err)
+			now = now.Add(tc.wait)
+			_, err = kickClient.GetUsers(context.Background(), gokick.NewUserListFilter())
+			require.NoError(t, err)
+
+			assert.Equal(t, tc.expectedCalls, calls.Load())
+		})
+	}
+}
+
+func TestResponseCacheErrorsNotCached(t *testing.T) {
+	kickClient, calls := setupCacheClient(t, gokick.NewResponseCache(gokick.ResponseCacheOptions{}),
+		func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"message":"not found"}`)
+		})
+
+	for range 2 {
+		_, err := kickClient.GetUsers(context.Background(), gokick.NewUserListFilter())
+		require.ErrorIs(t, err, gokick.ErrNotFound)
+	}
+
+	assert.Equal(t, int32(2), calls.Load())
+}
+
+func TestResponseCacheInvalidation(t *testing.T) {
+	var category atomic.Int32
+	category.Store(1)
+
+	cache := gokick.NewResponseCache(gokick.ResponseCacheOptions{})
+	kickClient, calls := setupCacheClient(t, cache, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPatch {
+			category.Store(2)
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		fmt.Fprintf(w, `{"message":"success", "data":[{"broadcaster_user_id": 1, "category": {"id": %d}}]}`, category.Load())
+	})
+
+	getCategory := func() int {
+		response, err := kickClient.GetChannels(context.Background(), gokick.NewChannelListFilter())
+		require.NoError(t, err)
+		return response.Result[0].Category.ID
+	}
+
+	assert.Equal(t, 1, getCategory())
+	assert.Equal(t, 1, getCategory())
+	assert.Equal(t, int32(1), calls.Load())
+
+	_, err := kickClient.UpdateStreamCategory(context.Background(), 2)
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, getCategory())
+	assert.Equal(t, int32(3), calls.Load())
+
+	cache.Invalidate("/public/v1/channels")
+	assert.Equal(t, 2, getCategory())
+	assert.Equal(t, int32(4), calls.Load())
+}
+
+func TestResponseCacheKeyedByToken(t *testing.T) {
+	kickClient, calls := setupCacheClient(t, gokick.NewResponseCache(gokick.ResponseCacheOptions{}),
+		func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprintf(w, `{"message":"success", "data":[{"name":%q}]}`, r.Header.Get("Authorization"))
+		})
+
+	first, err := kickClient.GetUsers(context.Background(), gokick.NewUserListFilter())
+	require.NoError(t, err)
+
+	second, err := kickClient.GetUsers(gokick.WithAccessToken(context.Background(), "other-token"), gokick.NewUserListFilter())
+	require.NoError(t, err)
+
+	assert.Equal(t, "Bearer access-token", first.Result[0].Name)
+	assert.Equal(t, "Bearer other-token", second.Result[0].Name)
+	assert.Equal(t, int32(2), calls.Load())
+}
+
+type failingCacheBackend struct{}
+
+func (failingCacheBackend) Get(context.Context, string) ([]byte, error) {
+	return nil, fmt.Errorf("backend down")
+}
+
+func (failingCacheBackend) Set(context.Context, string, []byte, time.Duration) error {
+	return fmt.Errorf("backend down")
+}
+
+func TestResponseCacheBackendFailure(t *testing.T) {
+	cache := gokick.NewResponseCache(gokick.ResponseCacheOptions{Backend: failingCacheBackend{}})
+	kickClient, calls := setupCacheClient(t, cache, usersHandler)
+
+	for range 2 {
+		response, err := kickClient.GetUsers(context.Background(), gokick.NewUserListFilter().SetID(117))
+		require.NoError(t, err)
+		assert.Equal(t, "user 117", response.Result[0].Name)
+	}
+
+	assert.Equal(t, int32(2), calls.Load())
+}
+
+func TestLRUCache(t *testing.T) {
+	cache := gokick.NewLRUCache(2)
+	ctx := context.Background()
+
+	_, err := cache.Get(ctx, "a")
+	require.ErrorIs(t, err, gokick.ErrCacheMiss)
+
+	require.NoError(t, cache.Set(ctx, "a", []byte("1"), time.Minute))
+	require.NoError(t, cache.Set(ctx, "b", []byte("2"), time.Minute))
+
+	value, err := cache.Get(ctx, "a")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("1"), value)
+
+	require.NoError(t, cache.Set(ctx, "c", []byte("3"), time.Minute))
+	assert.Equal(t, 2, cache.Len())
+
+	_, err = cache.Get(ctx, "b")
+	require.ErrorIs(t, err, gokick.ErrCacheMiss, "least recently used entry is evicted")
+
+	require.NoError(t, cache.Set(ctx, "a", []byte("4"), 0))
+	_, err = cache.Get(ctx, "a")
+	require.ErrorIs(t, err, gokick.ErrCacheMiss)
+	assert.Equal(t, 1, cache.Len())
+}
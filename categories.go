@@ -3,6 +3,7 @@ package gokick
 import (
 	"context"
 	"fmt"
+	"iter"
 	"net/http"
 	"net/url"
 	"strconv"
@@ -106,6 +107,22 @@ func (c *Client) GetCategories(ctx context.Context, filter CategoryListFilter) (
 	return CategoriesResponseWrapper(response), nil
 }
 
+// CategoriesPager returns a Pager over GetCategories, starting at the cursor of filter (if any).
+func (c *Client) CategoriesPager(filter CategoryListFilter) *Pager[CategoryResponse] {
+	pager := NewPager(func(ctx context.Context, cursor string) (PaginatedResponse[[]CategoryResponse], error) {
+		response, err := c.GetCategories(ctx, filter.SetCursor(cursor))
+		return PaginatedResponse[[]CategoryResponse](response), err
+	})
+	pager.cursor = filter.cursor
+
+	return pager
+}
+
+// AllCategories iterates over every category matching filter, fetching pages as needed.
+func (c *Client) AllCategories(ctx context.Context, filter CategoryListFilter) iter.Seq2[CategoryResponse, error] {
+	return c.CategoriesPager(filter).All(ctx)
+}
+
 func (c *Client) GetCategory(ctx context.Context, categoryID int) (CategoryResponseWrapper, error) {
 	filter := NewCategoryListFilter().AddID(categoryID).SetLimit(1)
 	list, err := c.GetCategories(ctx, filter)
@@ -115,5 +132,5 @@ func (c *Client) GetCategory(ctx context.Context, categoryID int) (CategoryRespo
 	if len(list.Result) == 0 {
 		return CategoryResponseWrapper{}, fmt.Errorf("category id %d: empty result", categoryID)
 	}
-	return CategoryResponseWrapper{Result: list.Result[0]}, nil
+	return CategoryResponseWrapper{Result: list.Result[0], Meta: list.Meta}, nil
 }
@@ -106,7 +106,7 @@ func (c *Client) UpdateStreamTitle(ctx context.Context, title string) (EmptyResp
 
 	body, err := json.Marshal(patchBodyRequest{StreamTitle: title})
 	if err != nil {
-		return EmptyResponse{}, fmt.Errorf("failed to marshal body: %v", err)
+		return EmptyResponse{}, fmt.Errorf("failed to marshal body: %w", err)
 	}
 
 	_, err = makeRequest[EmptyResponse](
@@ -131,7 +131,7 @@ func (c *Client) UpdateStreamCategory(ctx context.Context, categoryID int) (Empt
 
 	body, err := json.Marshal(patchBodyRequest{CategoryID: categoryID})
 	if err != nil {
-		return EmptyResponse{}, fmt.Errorf("failed to marshal body: %v", err)
+		return EmptyResponse{}, fmt.Errorf("failed to marshal body: %w", err)
 	}
 
 	_, err = makeRequest[EmptyResponse](
@@ -156,7 +156,7 @@ func (c *Client) UpdateStreamTags(ctx context.Context, tags []string) (EmptyResp
 
 	body, err := json.Marshal(patchBodyRequest{Tags: tags})
 	if err != nil {
-		return EmptyResponse{}, fmt.Errorf("failed to marshal body: %v", err)
+		return EmptyResponse{}, fmt.Errorf("failed to marshal body: %w", err)
 	}
 
 	_, err = makeRequest[EmptyResponse](
@@ -203,7 +203,7 @@ type CreateChannelRewardRequest struct {
 func (c *Client) CreateChannelReward(ctx context.Context, req CreateChannelRewardRequest) (Response[ChannelRewardResponse], error) {
 	body, err := json.Marshal(req)
 	if err != nil {
-		return Response[ChannelRewardResponse]{}, fmt.Errorf("failed to marshal body: %v", err)
+		return Response[ChannelRewardResponse]{}, fmt.Errorf("failed to marshal body: %w", err)
 	}
 
 	response, err := makeRequest[ChannelRewardResponse](
@@ -239,7 +239,7 @@ func (c *Client) UpdateChannelReward(
 ) (Response[ChannelRewardResponse], error) {
 	body, err := json.Marshal(req)
 	if err != nil {
-		return Response[ChannelRewardResponse]{}, fmt.Errorf("failed to marshal body: %v", err)
+		return Response[ChannelRewardResponse]{}, fmt.Errorf("failed to marshal body: %w", err)
 	}
 
 	response, err := makeRequest[ChannelRewardResponse](
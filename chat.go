@@ -67,7 +67,7 @@ func (c *Client) SendChatMessage(
 
 	body, err := json.Marshal(r)
 	if err != nil {
-		return ChatResponseWrapper{}, fmt.Errorf("failed to marshal body: %v", err)
+		return ChatResponseWrapper{}, fmt.Errorf("failed to marshal body: %w", err)
 	}
 
 	response, err := makeRequest[ChatResponse](
@@ -7,6 +7,10 @@ import (
 	"io"
 	"net/http"
 	"sync"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/sync/singleflight"
 )
 
 const (
@@ -15,15 +19,23 @@ const (
 )
 
 type Client struct {
-	options   *ClientOptions
-	mu        sync.Mutex
-	callbacks clientCallbacks
+	options           *ClientOptions
+	mu                sync.Mutex
+	callbacks         clientCallbacks
+	refreshGroup      singleflight.Group
+	refreshes         atomic.Int32
+	appTokenExpiresAt time.Time
+	tokenScopes       map[string]ScopeSet
+	transport         http.RoundTripper
 }
 
 type onUserAccessTokenRefreshedCallback func(accessToken, refreshToken string)
 
 type clientCallbacks struct {
 	onUserAccessTokenRefreshed onUserAccessTokenRefreshedCallback
+	// onUserAccessTokenPersist is called synchronously, before the refresh completes, unlike
+	// onUserAccessTokenRefreshed. It is set by ClientPool.
+	onUserAccessTokenPersist onUserAccessTokenRefreshedCallback
 }
 
 type ClientOptions struct {
@@ -35,6 +47,27 @@ type ClientOptions struct {
 	AuthBaseURL      string
 	ClientID         string
 	ClientSecret     string
+	// RetryPolicy enables retries of rate-limited, server and network errors. Nil disables retries.
+	RetryPolicy *RetryPolicy
+	// RateLimiter throttles requests on the client side. Nil disables throttling.
+	RateLimiter *RateLimiter
+	// TokenStore persists the user token pair and coordinates refreshes between clients sharing it.
+	TokenStore TokenStore
+	// UserAccessTokenExpiresAt is the expiry of UserAccessToken, when known. The client refreshes the token
+	// TokenRefreshMargin before it instead of waiting for a 401.
+	UserAccessTokenExpiresAt time.Time
+	// TokenRefreshMargin is how long before expiry the user token is refreshed. Defaults to one minute.
+	TokenRefreshMargin time.Duration
+	// AutoAppAccessToken lets the client obtain, cache and renew an app access token through the client
+	// credentials grant (ClientID and ClientSecret), for requests made without a user access token.
+	AutoAppAccessToken bool
+	// ScopePreflight makes the client check, before each request, that the user access token has the scopes the
+	// endpoint requires, and fail with a MissingScopeError otherwise. Scopes are introspected once per token.
+	ScopePreflight bool
+	// Middlewares wrap every call made by the client, the first one being the outermost.
+	Middlewares []Middleware
+	// Cache serves the responses of read-mostly endpoints from a ResponseCache. Nil disables caching.
+	Cache *ResponseCache
 }
 
 func NewClient(options *ClientOptions) (*Client, error) {
@@ -50,10 +83,17 @@ func NewClient(options *ClientOptions) (*Client, error) {
 		options.HTTPClient = &http.Client{}
 	}
 
-	return &Client{
+	if options.TokenRefreshMargin == 0 {
+		options.TokenRefreshMargin = defaultTokenRefreshMargin
+	}
+
+	client := &Client{
 		options: options,
 		mu:      sync.Mutex{},
-	}, nil
+	}
+	client.transport = client.pipeline(options.Middlewares)
+
+	return client, nil
 }
 
 type errorResponse struct {
@@ -73,9 +113,18 @@ func (c *Client) SetAppAccessToken(token string) {
 	c.mu.Unlock()
 }
 
+// SetUserAccessToken sets the user access token. Its expiry is unknown until SetUserAccessTokenExpiresAt is called.
 func (c *Client) SetUserAccessToken(token string) {
 	c.mu.Lock()
 	c.options.UserAccessToken = token
+	c.options.UserAccessTokenExpiresAt = time.Time{}
+	c.mu.Unlock()
+}
+
+// SetUserAccessTokenExpiresAt sets the expiry of the user access token, enabling proactive refresh.
+func (c *Client) SetUserAccessTokenExpiresAt(expiresAt time.Time) {
+	c.mu.Lock()
+	c.options.UserAccessTokenExpiresAt = expiresAt
 	c.mu.Unlock()
 }
 
@@ -96,34 +145,35 @@ func (c *Client) buildURL(base, path string) string {
 }
 
 func (c *Client) setRequestHeaders(req *http.Request) {
-	var token string
-	if c.options.AppAccessToken != "" {
-		token = c.options.AppAccessToken
-	}
-	if c.options.UserAccessToken != "" {
-		token = c.options.UserAccessToken
-	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
 
+	_, token := c.selectToken(req.Context())
 	if token != "" {
 		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
 	}
 }
 
 func (c *Client) refreshToken(ctx context.Context) error {
-	token, err := c.RefreshToken(ctx, c.options.UserRefreshToken)
-	if err != nil {
-		return fmt.Errorf("failed to refresh token: %w", err)
+	c.refreshes.Add(1)
+	defer c.refreshes.Add(-1)
+
+	if c.options.TokenStore != nil {
+		return c.refreshTokenWithStore(ctx, c.options.TokenStore)
 	}
 
 	c.mu.Lock()
-	c.options.UserAccessToken = token.AccessToken
-	c.options.UserRefreshToken = token.RefreshToken
+	refreshToken := c.options.UserRefreshToken
 	c.mu.Unlock()
 
-	if callback := c.callbacks.onUserAccessTokenRefreshed; callback != nil {
-		go callback(token.AccessToken, token.RefreshToken)
+	response, err := c.RefreshToken(ctx, refreshToken)
+	ContextTrace(ctx).tokenRefreshed("user", err)
+	if err != nil {
+		return fmt.Errorf("failed to refresh token: %w", err)
 	}
 
+	c.setUserToken(newTokenFromResponse(response))
+
 	return nil
 }
 
@@ -132,27 +182,40 @@ type contextKey string
 const retryKey contextKey = "retry"
 
 func (c *Client) do(req *http.Request) (*http.Response, error) {
-	var bodyReader *bytes.Reader
-	if req.Body != nil {
-		bodyBytes, err := io.ReadAll(req.Body)
-		if err != nil {
-			return nil, fmt.Errorf("failed to read request body: %w", err)
-		}
-		req.Body.Close()
+	bodyReader, err := bufferRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
 
-		bodyReader = bytes.NewReader(bodyBytes)
-		req.Body = io.NopCloser(bodyReader)
+	err = c.prepareToken(req)
+	if err != nil {
+		return nil, err
 	}
 
-	for {
-		c.setRequestHeaders(req)
+	err = c.checkScopes(req)
+	if err != nil {
+		return nil, err
+	}
 
-		response, err := c.options.HTTPClient.Do(req)
+	refreshed := false
+	for attempt := 1; ; attempt++ {
+		response, err := c.send(req)
 		if err != nil {
-			return nil, err
+			delay, retry := c.options.RetryPolicy.retryDelay(req, attempt, nil, err)
+			if !retry {
+				return nil, err
+			}
+			ContextTrace(req.Context()).retry(attempt, delay, nil, err)
+
+			err = waitAndRewind(req.Context(), delay, bodyReader)
+			if err != nil {
+				return nil, err
+			}
+
+			continue
 		}
 
-		if response.StatusCode == http.StatusUnauthorized && c.canRefreshUserToken() {
+		if response.StatusCode == http.StatusUnauthorized && !refreshed && c.canRenewToken(req.Context()) {
 			ctx := req.Context()
 			if ctx.Value(retryKey) == nil {
 				ctx = context.WithValue(ctx, retryKey, true)
@@ -160,27 +223,102 @@ func (c *Client) do(req *http.Request) (*http.Response, error) {
 				return response, nil
 			}
 
-			err := c.refreshToken(ctx)
+			_, _ = io.Copy(io.Discard, response.Body)
+			response.Body.Close()
+
+			err := c.renewToken(ctx, bearerToken(req))
 			if err != nil {
 				return nil, err
 			}
+			refreshed = true
 
-			if bodyReader != nil {
-				_, err := bodyReader.Seek(0, io.SeekStart)
-				if err != nil {
-					return nil, fmt.Errorf("failed to reset request body: %w", err)
-				}
+			err = rewindBody(bodyReader)
+			if err != nil {
+				return nil, err
 			}
 
 			continue
 		}
 
-		return response, nil
+		delay, retry := c.options.RetryPolicy.retryDelay(req, attempt, response, nil)
+		if !retry {
+			return response, nil
+		}
+		ContextTrace(req.Context()).retry(attempt, delay, response, nil)
+
+		_, _ = io.Copy(io.Discard, response.Body)
+		response.Body.Close()
+
+		err = waitAndRewind(req.Context(), delay, bodyReader)
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+// send performs a single attempt of the request.
+func (c *Client) send(req *http.Request) (*http.Response, error) {
+	err := c.waitRateLimit(req)
+	if err != nil {
+		return nil, err
+	}
+
+	c.setRequestHeaders(req)
+
+	response, err := c.options.HTTPClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	c.updateRateLimit(req, response)
+
+	return response, nil
+}
+
+func bufferRequestBody(req *http.Request) (*bytes.Reader, error) {
+	if req.Body == nil {
+		return nil, nil
+	}
+
+	bodyBytes, err := io.ReadAll(req.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read request body: %w", err)
+	}
+	req.Body.Close()
+
+	bodyReader := bytes.NewReader(bodyBytes)
+	req.Body = io.NopCloser(bodyReader)
+
+	return bodyReader, nil
+}
+
+func waitAndRewind(ctx context.Context, delay time.Duration, bodyReader *bytes.Reader) error {
+	err := sleepContext(ctx, delay)
+	if err != nil {
+		return err
 	}
+
+	return rewindBody(bodyReader)
+}
+
+func rewindBody(bodyReader *bytes.Reader) error {
+	if bodyReader == nil {
+		return nil
+	}
+
+	_, err := bodyReader.Seek(0, io.SeekStart)
+	if err != nil {
+		return fmt.Errorf("failed to reset request body: %w", err)
+	}
+
+	return nil
 }
 
 func (c *Client) canRefreshUserToken() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
 	return c.options.ClientID != "" &&
 		c.options.ClientSecret != "" &&
-		c.options.UserRefreshToken != ""
+		(c.options.UserRefreshToken != "" || c.options.TokenStore != nil)
 }
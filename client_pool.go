@@ -0,0 +1,219 @@
+package gokick
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// ClientPoolOptions configures a ClientPool.
+type ClientPoolOptions struct {
+	// ClientOptions is the template every client of the pool is built from. Its HTTPClient is shared by all
+	// clients (a new one is created when nil), as are its RetryPolicy and Cache. Each client gets its own
+	// RateLimiter with the limits of the template one, so that a broadcaster rate limited by Kick does not block
+	// the others. Its user token fields and TokenStore are ignored: tokens come from LoadToken and TokenStore below.
+	ClientOptions ClientOptions
+	// LoadToken returns the user token pair of userID when its client is built.
+	LoadToken func(ctx context.Context, userID int) (Token, error)
+	// TokenStore returns the token store of userID, if any. It is used instead of, or after, LoadToken.
+	TokenStore func(userID int) TokenStore
+	// IdleTimeout is how long a client can go without being requested before it is evicted. Zero disables eviction.
+	IdleTimeout time.Duration
+}
+
+type onPoolUserAccessTokenRefreshedCallback func(userID int, accessToken, refreshToken string)
+
+type pooledClient struct {
+	client   *Client
+	lastUsed time.Time
+}
+
+// ClientPool lazily builds and caches one Client per broadcaster user ID, for applications acting on behalf of
+// many broadcasters. Each client refreshes its own user token independently of the others.
+//
+// Idle clients are evicted when other clients are requested, unless they are refreshing their user token. Request
+// the client from the pool for each unit of work instead of keeping it around: a client kept after its eviction
+// refreshes the same token pair as the client replacing it, which fails once Kick rotated the refresh token.
+type ClientPool struct {
+	options     ClientPoolOptions
+	httpClient  *http.Client
+	mu          sync.Mutex
+	clients     map[int]*pooledClient
+	lastSweep   time.Time
+	onRefreshed onPoolUserAccessTokenRefreshedCallback
+	buildGroup  singleflight.Group
+	now         func() time.Time
+}
+
+func NewClientPool(options ClientPoolOptions) *ClientPool {
+	httpClient := options.ClientOptions.HTTPClient
+	if httpClient == nil {
+		httpClient = &http.Client{}
+	}
+
+	return &ClientPool{
+		options:    options,
+		httpClient: httpClient,
+		clients:    make(map[int]*pooledClient),
+		now:        time.Now,
+	}
+}
+
+// OnUserAccessTokenRefreshed sets the callback fired whenever the user token of one of the clients is refreshed.
+// It applies to clients already in the pool as well as to future ones. It is called synchronously, before the
+// refresh completes, so that a client evicted once the refresh is over is rebuilt by LoadToken with the new token
+// pair.
+func (p *ClientPool) OnUserAccessTokenRefreshed(callback onPoolUserAccessTokenRefreshedCallback) {
+	p.mu.Lock()
+	p.onRefreshed = callback
+	p.mu.Unlock()
+}
+
+// Get returns the client of userID, building it on first use. Concurrent calls for the same user share a single
+// build, which is not canceled by them; each caller stops waiting when its own context is done.
+func (p *ClientPool) Get(ctx context.Context, userID int) (*Client, error) {
+	p.mu.Lock()
+	now := p.now()
+	p.sweep(now)
+	entry, ok := p.clients[userID]
+	if ok {
+		entry.lastUsed = now
+	}
+	p.mu.Unlock()
+
+	if ok {
+		return entry.client, nil
+	}
+
+	result := p.buildGroup.DoChan(strconv.Itoa(userID), func() (interface{}, error) {
+		p.mu.Lock()
+		entry, ok := p.clients[userID]
+		p.mu.Unlock()
+		if ok {
+			return entry.client, nil
+		}
+
+		client, err := p.newClient(context.WithoutCancel(ctx), userID)
+		if err != nil {
+			return nil, err
+		}
+
+		p.mu.Lock()
+		p.clients[userID] = &pooledClient{client: client, lastUsed: p.now()}
+		p.mu.Unlock()
+
+		return client, nil
+	})
+
+	select {
+	case r := <-result:
+		if r.Err != nil {
+			return nil, r.Err
+		}
+
+		return r.Val.(*Client), nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// Remove drops the client of userID from the pool, for example once the broadcaster revoked the application.
+func (p *ClientPool) Remove(userID int) {
+	p.mu.Lock()
+	delete(p.clients, userID)
+	p.mu.Unlock()
+}
+
+// EvictIdle drops the clients not requested for IdleTimeout and returns how many were evicted.
+func (p *ClientPool) EvictIdle() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.evictIdle(p.now())
+}
+
+// Len returns the number of clients in the pool.
+func (p *ClientPool) Len() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return len(p.clients)
+}
+
+func (p *ClientPool) newClient(ctx context.Context, userID int) (*Client, error) {
+	options := p.options.ClientOptions
+	options.HTTPClient = p.httpClient
+	options.UserAccessToken = ""
+	options.UserRefreshToken = ""
+	options.UserAccessTokenExpiresAt = time.Time{}
+	options.TokenStore = nil
+	if options.RateLimiter != nil {
+		options.RateLimiter = options.RateLimiter.clone()
+	}
+
+	if p.options.TokenStore != nil {
+		options.TokenStore = p.options.TokenStore(userID)
+	}
+
+	if p.options.LoadToken != nil {
+		token, err := p.options.LoadToken(ctx, userID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load token of user %d: %w", userID, err)
+		}
+
+		options.UserAccessToken = token.AccessToken
+		options.UserRefreshToken = token.RefreshToken
+		options.UserAccessTokenExpiresAt = token.ExpiresAt
+	}
+
+	client, err := NewClient(&options)
+	if err != nil {
+		return nil, err
+	}
+
+	client.mu.Lock()
+	client.callbacks.onUserAccessTokenPersist = func(accessToken, refreshToken string) {
+		p.mu.Lock()
+		callback := p.onRefreshed
+		p.mu.Unlock()
+
+		if callback != nil {
+			callback(userID, accessToken, refreshToken)
+		}
+	}
+	client.mu.Unlock()
+
+	return client, nil
+}
+
+// sweep evicts idle clients at most once per IdleTimeout. p.mu must be held.
+func (p *ClientPool) sweep(now time.Time) {
+	if p.options.IdleTimeout <= 0 || now.Sub(p.lastSweep) < p.options.IdleTimeout {
+		return
+	}
+
+	p.evictIdle(now)
+}
+
+// evictIdle drops the clients idle since IdleTimeout and not refreshing their user token. p.mu must be held.
+func (p *ClientPool) evictIdle(now time.Time) int {
+	p.lastSweep = now
+	if p.options.IdleTimeout <= 0 {
+		return 0
+	}
+
+	evicted := 0
+	for userID, entry := range p.clients {
+		if now.Sub(entry.lastUsed) >= p.options.IdleTimeout && entry.client.refreshes.Load() == 0 {
+			delete(p.clients, userID)
+			evicted++
+		}
+	}
+
+	return evicted
+}
@@ -0,0 +1,314 @@
+package gokick_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/scorfly/gokick"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type countingRoundTripper struct {
+	requests atomic.Int32
+}
+
+func (rt *countingRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	rt.requests.Add(1)
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+// setupClientPoolServer accepts "<user>-access" tokens, rejects "<user>-expired" ones and refreshes
+// "<user>-refresh" into "<user>-access".
+func setupClientPoolServer(t *testing.T) string {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/oauth/token" {
+			var user int
+			_, _ = fmt.Sscanf(r.FormValue("refresh_token"), "%d-refresh", &user)
+			fmt.Fprintf(w, `{"access_token":"%d-access","refresh_token":"%d-refresh","expires_in":7200}`, user, user)
+			return
+		}
+
+		var user int
+		var state string
+		_, _ = fmt.Sscanf(r.Header.Get("Authorization"), "Bearer %d-%s", &user, &state)
+		if state != "access" {
+			w.WriteHeader(http.StatusUnauthorized)
+			fmt.Fprint(w, `{"message":"unauthorized"}`)
+			return
+		}
+
+		fmt.Fprintf(w, `{"data":[{"user_id":%d}]}`, user)
+	}))
+	t.Cleanup(server.Close)
+
+	return server.URL
+}
+
+func newTestClientPool(
+	t *testing.T,
+	idleTimeout time.Duration,
+	now func() time.Time,
+	loads *atomic.Int32,
+) (*gokick.ClientPool, *countingRoundTripper) {
+	t.Helper()
+
+	baseURL := setupClientPoolServer(t)
+	transport := &countingRoundTripper{}
+
+	pool := gokick.NewClientPool(gokick.ClientPoolOptions{
+		ClientOptions: gokick.ClientOptions{
+			ClientID:     "client-id",
+			ClientSecret: "client-secret",
+			APIBaseURL:   baseURL,
+			AuthBaseURL:  baseURL,
+			HTTPClient:   &http.Client{Transport: transport},
+		},
+		LoadToken: func(_ context.Context, userID int) (gokick.Token, error) {
+			loads.Add(1)
+			if userID == 0 {
+				return gokick.Token{}, errors.New("unknown user")
+			}
+			return gokick.Token{AccessToken: fmt.Sprintf("%d-expired", userID), RefreshToken: fmt.Sprintf("%d-refresh", userID)}, nil
+		},
+		IdleTimeout: idleTimeout,
+	})
+	if now != nil {
+		gokick.SetClientPoolNow(pool, now)
+	}
+
+	return pool, transport
+}
+
+func TestClientPool(t *testing.T) {
+	t.Run("clients act for their own user", func(t *testing.T) {
+		var loads atomic.Int32
+		pool, transport := newTestClientPool(t, 0, nil, &loads)
+
+		type refresh struct {
+			userID      int
+			accessToken string
+		}
+		refreshes := make(chan refresh, 2)
+		pool.OnUserAccessTokenRefreshed(func(userID int, accessToken, _ string) {
+			refreshes <- refresh{userID: userID, accessToken: accessToken}
+		})
+
+		for _, userID := range []int{1, 2} {
+			kickClient, err := pool.Get(context.Background(), userID)
+			require.NoError(t, err)
+
+			users, err := kickClient.GetUsers(context.Background(), gokick.NewUserListFilter())
+			require.NoError(t, err)
+			assert.Equal(t, userID, users.Result[0].UserID)
+			assert.Equal(t, refresh{userID: userID, accessToken: fmt.Sprintf("%d-access", userID)}, <-refreshes)
+		}
+
+		assert.Equal(t, 2, pool.Len())
+		assert.Equal(t, int32(6), transport.requests.Load())
+	})
+
+	t.Run("concurrent gets build the client once", func(t *testing.T) {
+		var loads atomic.Int32
+		pool, _ := newTestClientPool(t, 0, nil, &loads)
+
+		clients := make([]*gokick.Client, 20)
+		var wg sync.WaitGroup
+		for i := range clients {
+			wg.Go(func() {
+				kickClient, err := pool.Get(context.Background(), 1)
+				assert.NoError(t, err)
+				clients[i] = kickClient
+			})
+		}
+		wg.Wait()
+
+		assert.Equal(t, int32(1), loads.Load())
+		for _, kickClient := range clients {
+			assert.Same(t, clients[0], kickClient)
+		}
+	})
+
+	t.Run("canceled get does not fail the shared build", func(t *testing.T) {
+		baseURL := setupClientPoolServer(t)
+		loading := make(chan struct{})
+		release := make(chan struct{})
+
+		pool := gokick.NewClientPool(gokick.ClientPoolOptions{
+			ClientOptions: gokick.ClientOptions{APIBaseURL: baseURL, AuthBaseURL: baseURL},
+			LoadToken: func(ctx context.Context, _ int) (gokick.Token, error) {
+				close(loading)
+				<-release
+				err := ctx.Err()
+				if err != nil {
+					return gokick.Token{}, err
+				}
+				return gokick.Token{AccessToken: "1-access", RefreshToken: "1-refresh"}, nil
+			},
+		})
+
+		ctx, cancel := context.WithCancel(context.Background())
+		canceled := make(chan error, 1)
+		go func() {
+			_, err := pool.Get(ctx, 1)
+			canceled <- err
+		}()
+		<-loading
+
+		waiting := make(chan error, 1)
+		go func() {
+			_, err := pool.Get(context.Background(), 1)
+			waiting <- err
+		}()
+
+		cancel()
+		require.ErrorIs(t, <-canceled, context.Canceled)
+
+		close(release)
+		require.NoError(t, <-waiting)
+		assert.Equal(t, 1, pool.Len())
+	})
+
+	t.Run("idle clients are evicted", func(t *testing.T) {
+		var loads atomic.Int32
+		now := time.Now()
+		pool, _ := newTestClientPool(t, time.Minute, func() time.Time { return now }, &loads)
+
+		_, err := pool.Get(context.Background(), 1)
+		require.NoError(t, err)
+		_, err = pool.Get(context.Background(), 2)
+		require.NoError(t, err)
+		assert.Equal(t, 0, pool.EvictIdle())
+
+		now = now.Add(time.Minute)
+
+		_, err = pool.Get(context.Background(), 1)
+		require.NoError(t, err)
+		assert.Equal(t, 1, pool.Len())
+		assert.Equal(t, int32(3), loads.Load())
+
+		now = now.Add(time.Minute)
+		assert.Equal(t, 1, pool.EvictIdle())
+		assert.Equal(t, 0, pool.Len())
+	})
+
+	t.Run("clients refreshing their token are not evicted", func(t *testing.T) {
+		var loads atomic.Int32
+		now := time.Now()
+		pool, _ := newTestClientPool(t, time.Minute, func() time.Time { return now }, &loads)
+
+		refreshing := make(chan struct{})
+		release := make(chan struct{})
+		var saved string
+		pool.OnUserAccessTokenRefreshed(func(_ int, accessToken, _ string) {
+			close(refreshing)
+			<-release
+			saved = accessToken
+		})
+
+		kickClient, err := pool.Get(context.Background(), 1)
+		require.NoError(t, err)
+
+		done := make(chan error)
+		go func() {
+			_, err := kickClient.GetUsers(context.Background(), gokick.NewUserListFilter())
+			done <- err
+		}()
+
+		<-refreshing
+		now = now.Add(time.Minute)
+		assert.Equal(t, 0, pool.EvictIdle())
+
+		close(release)
+		require.NoError(t, <-done)
+		assert.Equal(t, "1-access", saved, "the token is saved before the refresh completes")
+		assert.Equal(t, 1, pool.EvictIdle())
+	})
+
+	t.Run("clients have their own rate limiter", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Authorization") == "Bearer 1-access" {
+				w.Header().Set("Retry-After", "3600")
+				w.WriteHeader(http.StatusTooManyRequests)
+				fmt.Fprint(w, `{"message":"too many requests"}`)
+				return
+			}
+
+			fmt.Fprint(w, `{"data":[{"user_id":2}]}`)
+		}))
+		t.Cleanup(server.Close)
+
+		pool := gokick.NewClientPool(gokick.ClientPoolOptions{
+			ClientOptions: gokick.ClientOptions{
+				APIBaseURL:  server.URL,
+				RateLimiter: gokick.NewRateLimiter(gokick.RateLimit{}),
+			},
+			LoadToken: func(_ context.Context, userID int) (gokick.Token, error) {
+				return gokick.Token{AccessToken: fmt.Sprintf("%d-access", userID)}, nil
+			},
+		})
+
+		rateLimited, err := pool.Get(context.Background(), 1)
+		require.NoError(t, err)
+		_, err = rateLimited.GetUsers(context.Background(), gokick.NewUserListFilter())
+		require.ErrorIs(t, err, gokick.ErrRateLimited)
+
+		other, err := pool.Get(context.Background(), 2)
+		require.NoError(t, err)
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		users, err := other.GetUsers(ctx, gokick.NewUserListFilter())
+		require.NoError(t, err)
+		assert.Equal(t, 2, users.Result[0].UserID)
+	})
+
+	t.Run("remove", func(t *testing.T) {
+		var loads atomic.Int32
+		pool, _ := newTestClientPool(t, 0, nil, &loads)
+
+		_, err := pool.Get(context.Background(), 1)
+		require.NoError(t, err)
+		pool.Remove(1)
+		assert.Equal(t, 0, pool.Len())
+
+		_, err = pool.Get(context.Background(), 1)
+		require.NoError(t, err)
+		assert.Equal(t, int32(2), loads.Load())
+	})
+
+	t.Run("load token error", func(t *testing.T) {
+		var loads atomic.Int32
+		pool, _ := newTestClientPool(t, 0, nil, &loads)
+
+		_, err := pool.Get(context.Background(), 0)
+		require.EqualError(t, err, "failed to load token of user 0: unknown user")
+		assert.Equal(t, 0, pool.Len())
+	})
+
+	t.Run("token store per user", func(t *testing.T) {
+		baseURL := setupClientPoolServer(t)
+		stores := map[int]gokick.TokenStore{1: gokick.NewMemoryTokenStore()}
+		require.NoError(t, stores[1].Save(context.Background(), gokick.Token{AccessToken: "1-access", RefreshToken: "1-refresh"}))
+
+		pool := gokick.NewClientPool(gokick.ClientPoolOptions{
+			ClientOptions: gokick.ClientOptions{APIBaseURL: baseURL, AuthBaseURL: baseURL},
+			TokenStore:    func(userID int) gokick.TokenStore { return stores[userID] },
+		})
+
+		kickClient, err := pool.Get(context.Background(), 1)
+		require.NoError(t, err)
+
+		users, err := kickClient.GetUsers(context.Background(), gokick.NewUserListFilter())
+		require.NoError(t, err)
+		assert.Equal(t, 1, users.Result[0].UserID)
+	})
+}
@@ -1,17 +1,56 @@
 package gokick
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"time"
+)
+
+// Sentinel errors matched with errors.Is by the Error returned for a failed Kick API call, depending on its
+// status code.
+var (
+	ErrUnauthorized = errors.New("gokick: unauthorized")
+	ErrForbidden    = errors.New("gokick: forbidden")
+	ErrNotFound     = errors.New("gokick: not found")
+	ErrRateLimited  = errors.New("gokick: rate limited")
+	ErrValidation   = errors.New("gokick: validation failed")
+	ErrServer       = errors.New("gokick: server error")
+)
 
 type Error struct {
 	code        int
 	message     string
 	description string
+	method      string
+	endpoint    string
+	retryAfter  time.Duration
+	// body is a string and fields a pointer, to keep Error comparable.
+	body   string
+	fields *map[string][]string
 }
 
 func NewError(code int, message string) Error {
 	return Error{code: code, message: message}
 }
 
+// newErrorFromResponse returns the Error of a failed call, without message.
+func newErrorFromResponse(resp *http.Response, body []byte) Error {
+	e := Error{code: resp.StatusCode, body: string(body)}
+	if resp.Request != nil {
+		e.method = resp.Request.Method
+		e.endpoint = resp.Request.URL.Path
+	}
+
+	retryAfter, ok := parseRetryAfter(resp.Header.Get("Retry-After"), time.Now())
+	if ok {
+		e.retryAfter = retryAfter
+	}
+
+	return e
+}
+
 func (e Error) WithDescription(description string) Error {
 	e.description = description
 	return e
@@ -29,6 +68,58 @@ func (e Error) Description() string {
 	return e.description
 }
 
+// Method returns the HTTP method of the failed call.
+func (e Error) Method() string {
+	return e.method
+}
+
+// Endpoint returns the path of the failed call, without query string.
+func (e Error) Endpoint() string {
+	return e.endpoint
+}
+
+// Body returns the raw response body.
+func (e Error) Body() []byte {
+	return []byte(e.body)
+}
+
+// RetryAfter returns the delay requested by the Retry-After header, or 0 when absent.
+func (e Error) RetryAfter() time.Duration {
+	return e.retryAfter
+}
+
+// Fields returns the validation errors of each field, as reported in the data field of the response.
+func (e Error) Fields() map[string][]string {
+	if e.fields == nil {
+		return nil
+	}
+
+	fields := make(map[string][]string, len(*e.fields))
+	for field, messages := range *e.fields {
+		fields[field] = slices.Clone(messages)
+	}
+
+	return fields
+}
+
+// Is reports whether target is the sentinel error matching the status code of e.
+func (e Error) Is(target error) bool {
+	switch e.code {
+	case http.StatusBadRequest, http.StatusUnprocessableEntity:
+		return target == ErrValidation
+	case http.StatusUnauthorized:
+		return target == ErrUnauthorized
+	case http.StatusForbidden:
+		return target == ErrForbidden
+	case http.StatusNotFound:
+		return target == ErrNotFound
+	case http.StatusTooManyRequests:
+		return target == ErrRateLimited
+	default:
+		return e.code >= http.StatusInternalServerError && target == ErrServer
+	}
+}
+
 func (e Error) Error() string {
 	if e.description == "" {
 		return fmt.Sprintf("Error %d: %s", e.code, e.message)
@@ -36,3 +127,48 @@ func (e Error) Error() string {
 		return fmt.Sprintf("Error %d: %s (%s)", e.code, e.message, e.description)
 	}
 }
+
+// undecodedError is returned when the body of a failed call cannot be decoded. It keeps the decoding error
+// message while still unwrapping to the Error of the call.
+type undecodedError struct {
+	err     error
+	kickErr Error
+}
+
+func (e undecodedError) Error() string {
+	return e.err.Error()
+}
+
+func (e undecodedError) Unwrap() []error {
+	return []error{e.err, e.kickErr}
+}
+
+// validationFields extracts field errors from the data field of an error response, such as
+// {"title": ["is too long"]} or {"title": "is too long"}.
+func validationFields(data interface{}) *map[string][]string {
+	object, ok := data.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+
+	fields := make(map[string][]string)
+	for field, value := range object {
+		switch value := value.(type) {
+		case string:
+			fields[field] = []string{value}
+		case []interface{}:
+			for _, item := range value {
+				message, ok := item.(string)
+				if ok {
+					fields[field] = append(fields[field], message)
+				}
+			}
+		}
+	}
+
+	if len(fields) == 0 {
+		return nil
+	}
+
+	return &fields
+}
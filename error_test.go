@@ -1,7 +1,13 @@
 package gokick_test
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
 	"testing"
+	"time"
 
 	"github.com/scorfly/gokick"
 	"github.com/stretchr/testify/assert"
@@ -31,3 +37,121 @@ func TestNewError(t *testing.T) {
 		assert.EqualError(t, err, "Error 401: not authorized (invalid scope)")
 	})
 }
+
+func TestErrorComparable(t *testing.T) {
+	err := gokick.NewError(401, "not authorized")
+	equal := err == gokick.NewError(401, "not authorized")
+	assert.True(t, equal)
+
+	kickClient := setupMockClient(t, func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		fmt.Fprint(w, `{"message":"invalid request","data":{"title":"is required"}}`)
+	})
+
+	_, callErr := kickClient.UpdateStreamTitle(context.Background(), "")
+	require.Error(t, callErr)
+	assert.NotPanics(t, func() { equal = callErr == error(err) })
+	assert.False(t, equal)
+
+	var kickError gokick.Error
+	require.ErrorAs(t, callErr, &kickError)
+	kickError.Fields()["title"][0] = "changed"
+	assert.Equal(t, map[string][]string{"title": {"is required"}}, kickError.Fields())
+}
+
+func TestErrorSentinels(t *testing.T) {
+	sentinels := []error{
+		gokick.ErrValidation, gokick.ErrUnauthorized, gokick.ErrForbidden, gokick.ErrNotFound, gokick.ErrRateLimited, gokick.ErrServer,
+	}
+
+	testCases := map[int]error{
+		http.StatusBadRequest:          gokick.ErrValidation,
+		http.StatusUnauthorized:        gokick.ErrUnauthorized,
+		http.StatusForbidden:           gokick.ErrForbidden,
+		http.StatusNotFound:            gokick.ErrNotFound,
+		http.StatusConflict:            nil,
+		http.StatusUnprocessableEntity: gokick.ErrValidation,
+		http.StatusTooManyRequests:     gokick.ErrRateLimited,
+		http.StatusBadGateway:          gokick.ErrServer,
+	}
+
+	for code, expected := range testCases {
+		t.Run(http.StatusText(code), func(t *testing.T) {
+			err := fmt.Errorf("wrapped: %w", gokick.NewError(code, "message"))
+			for _, sentinel := range sentinels {
+				assert.Equal(t, sentinel == expected, errors.Is(err, sentinel), sentinel.Error())
+			}
+		})
+	}
+}
+
+func TestErrorFromResponse(t *testing.T) {
+	t.Run("validation details", func(t *testing.T) {
+		body := `{"message":"invalid request","data":{"title":["is required","is too short"],"category_id":"is invalid","other":1}}`
+		kickClient := setupMockClient(t, func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			fmt.Fprint(w, body)
+		})
+
+		_, err := kickClient.UpdateStreamTitle(context.Background(), "")
+		require.ErrorIs(t, err, gokick.ErrValidation)
+		require.EqualError(t, err, "Error 422: invalid request")
+
+		var kickError gokick.Error
+		require.ErrorAs(t, err, &kickError)
+		assert.Equal(t, http.MethodPatch, kickError.Method())
+		assert.Equal(t, "/public/v1/channels", kickError.Endpoint())
+		assert.Equal(t, body, string(kickError.Body()))
+		assert.Equal(t, map[string][]string{"title": {"is required", "is too short"}, "category_id": {"is invalid"}}, kickError.Fields())
+	})
+
+	t.Run("retry after", func(t *testing.T) {
+		kickClient := setupMockClient(t, func(w http.ResponseWriter, _ *http.Request) {
+			w.Header().Set("Retry-After", "30")
+			w.WriteHeader(http.StatusTooManyRequests)
+			fmt.Fprint(w, `{"message":"too many requests","data":[]}`)
+		})
+
+		_, err := kickClient.GetUsers(context.Background(), gokick.NewUserListFilter().SetID(1))
+		require.ErrorIs(t, err, gokick.ErrRateLimited)
+
+		var kickError gokick.Error
+		require.ErrorAs(t, err, &kickError)
+		assert.Equal(t, 30*time.Second, kickError.RetryAfter())
+		assert.Equal(t, "/public/v1/users", kickError.Endpoint())
+		assert.Nil(t, kickError.Fields())
+	})
+
+	t.Run("undecodable body keeps the status", func(t *testing.T) {
+		kickClient := setupMockClient(t, func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `<html>not found</html>`)
+		})
+
+		_, err := kickClient.GetUsers(context.Background(), gokick.NewUserListFilter())
+		require.ErrorIs(t, err, gokick.ErrNotFound)
+		require.ErrorContains(t, err, "failed to unmarshal error response (KICK status code: 404")
+
+		var syntaxErr *json.SyntaxError
+		require.ErrorAs(t, err, &syntaxErr)
+
+		var kickError gokick.Error
+		require.ErrorAs(t, err, &kickError)
+		assert.Equal(t, "<html>not found</html>", string(kickError.Body()))
+	})
+
+	t.Run("oauth error", func(t *testing.T) {
+		kickClient := setupMockAuthClient(t, func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusUnauthorized)
+			fmt.Fprint(w, `{"error":"invalid_client","error_description":"unknown client"}`)
+		})
+
+		_, err := kickClient.GetAppAccessToken(context.Background())
+		require.ErrorIs(t, err, gokick.ErrUnauthorized)
+
+		var kickError gokick.Error
+		require.ErrorAs(t, err, &kickError)
+		assert.Equal(t, "/oauth/token", kickError.Endpoint())
+		assert.Equal(t, "unknown client", kickError.Description())
+	})
+}
@@ -91,7 +91,7 @@ func (c *Client) CreateSubscriptions(
 
 	body, err := json.Marshal(r)
 	if err != nil {
-		return CreateSubscriptionsResponseWrapper{}, fmt.Errorf("failed to marshal body: %v", err)
+		return CreateSubscriptionsResponseWrapper{}, fmt.Errorf("failed to marshal body: %w", err)
 	}
 
 	response, err := makeRequest[[]CreateSubscriptionResponse](
@@ -0,0 +1,34 @@
+package gokick
+
+import "time"
+
+// SetClientPoolNow replaces the clock against which p checks the idle timeout of its clients.
+func SetClientPoolNow(p *ClientPool, now func() time.Time) {
+	p.now = now
+}
+
+// SetOAuthFlowNow replaces the clock against which f checks the age of its states.
+func SetOAuthFlowNow(f *OAuthFlow, now func() time.Time) {
+	f.now = now
+}
+
+// SetResponseCacheNow replaces the clock of rc and, with the default backend, the one its entries expire with.
+func SetResponseCacheNow(rc *ResponseCache, now func() time.Time) {
+	rc.now = now
+	if backend, ok := rc.backend.(*LRUCache); ok {
+		backend.now = now
+	}
+}
+
+// SetReplayProtectionNow replaces the clock of p and, with the default store, the one message IDs expire with.
+func SetReplayProtectionNow(p *ReplayProtection, now func() time.Time) {
+	p.now = now
+	if store, ok := p.store.(*MemorySeenStore); ok {
+		store.now = now
+	}
+}
+
+// SetFetchingKeyProviderNow replaces the clock against which p checks the minimum refresh interval.
+func SetFetchingKeyProviderNow(p *FetchingKeyProvider, now func() time.Time) {
+	p.now = now
+}
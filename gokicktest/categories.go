@@ -0,0 +1,115 @@
+package gokicktest
+
+import (
+	"encoding/base64"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/scorfly/gokick"
+)
+
+const (
+	defaultCategoriesLimit = 25
+	maxCategoriesLimit     = 100
+)
+
+// AddCategory adds a category, or replaces the category with the same ID.
+func (s *Server) AddCategory(category gokick.CategoryResponse) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	index := slices.IndexFunc(s.categories, func(c gokick.CategoryResponse) bool { return c.ID == category.ID })
+	if index >= 0 {
+		s.categories[index] = category
+		return
+	}
+
+	s.categories = append(s.categories, category)
+}
+
+func (s *Server) registerCategoryRoutes() {
+	s.mux.HandleFunc("GET /public/v2/categories", s.handleGetCategories)
+}
+
+// handleGetCategories lists the categories matching the name (substring), tag and id parameters, by pages of limit
+// categories, the cursor being the opaque offset of the page.
+func (s *Server) handleGetCategories(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	limit, offset, ok := pageParameters(query.Get("limit"), query.Get("cursor"))
+	if !ok {
+		writeError(w, http.StatusBadRequest, "Invalid limit or cursor")
+		return
+	}
+
+	s.mu.Lock()
+	categories := slices.DeleteFunc(slices.Clone(s.categories), func(category gokick.CategoryResponse) bool {
+		return !categoryMatches(category, splitParameter(query.Get("name")), splitParameter(query.Get("tag")),
+			splitParameter(query.Get("id")))
+	})
+	s.mu.Unlock()
+
+	response := gokick.PaginatedResponse[[]gokick.CategoryResponse]{Result: []gokick.CategoryResponse{}}
+	if offset < len(categories) {
+		end := min(offset+limit, len(categories))
+		response.Result = categories[offset:end]
+		if end < len(categories) {
+			response.Pagination.NextCursor = base64.RawURLEncoding.EncodeToString([]byte(strconv.Itoa(end)))
+		}
+	}
+
+	writeJSON(w, http.StatusOK, response)
+}
+
+func pageParameters(limitValue, cursor string) (int, int, bool) {
+	limit := defaultCategoriesLimit
+	if limitValue != "" {
+		var err error
+		limit, err = strconv.Atoi(limitValue)
+		if err != nil || limit < 1 || limit > maxCategoriesLimit {
+			return 0, 0, false
+		}
+	}
+
+	if cursor == "" {
+		return limit, 0, true
+	}
+
+	decoded, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, 0, false
+	}
+
+	offset, err := strconv.Atoi(string(decoded))
+	if err != nil || offset < 0 {
+		return 0, 0, false
+	}
+
+	return limit, offset, true
+}
+
+func categoryMatches(category gokick.CategoryResponse, names, tags, ids []string) bool {
+	if len(names) > 0 && !slices.ContainsFunc(names, func(name string) bool {
+		return strings.Contains(strings.ToLower(category.Name), strings.ToLower(name))
+	}) {
+		return false
+	}
+
+	if len(tags) > 0 && !slices.ContainsFunc(tags, func(tag string) bool {
+		return slices.ContainsFunc(category.Tags, func(categoryTag string) bool { return strings.EqualFold(categoryTag, tag) })
+	}) {
+		return false
+	}
+
+	return len(ids) == 0 || slices.Contains(ids, strconv.Itoa(category.ID))
+}
+
+func splitParameter(value string) []string {
+	if value == "" {
+		return nil
+	}
+
+	return strings.Split(value, ",")
+}
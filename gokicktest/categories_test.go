@@ -0,0 +1,38 @@
+package gokicktest_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/scorfly/gokick"
+	"github.com/scorfly/gokick/gokicktest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCategories(t *testing.T) {
+	server := gokicktest.NewServer(t)
+	for id := 10; id < 40; id++ {
+		server.AddCategory(gokick.CategoryResponse{ID: id, Name: fmt.Sprintf("Game %d", id), Tags: []string{"Game"}})
+	}
+	client := server.Client()
+
+	var names []string
+	for category, err := range client.AllCategories(context.Background(), gokick.NewCategoryListFilter().AddTag("game").SetLimit(7)) {
+		require.NoError(t, err)
+		names = append(names, category.Name)
+	}
+	assert.Len(t, names, 30)
+	server.AssertRequestCount(t, "GET", "/public/v2/categories", 5)
+
+	byName, err := client.GetCategories(context.Background(), gokick.NewCategoryListFilter().AddName("counter"))
+	require.NoError(t, err)
+	require.Len(t, byName.Result, 1)
+	assert.Equal(t, 2, byName.Result[0].ID)
+	assert.Empty(t, byName.Pagination.NextCursor)
+
+	category, err := client.GetCategory(context.Background(), 3)
+	require.NoError(t, err)
+	assert.Equal(t, "Slots & Casino", category.Result.Name)
+}
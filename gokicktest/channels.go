@@ -0,0 +1,268 @@
+package gokicktest
+
+import (
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/scorfly/gokick"
+)
+
+// AddUser adds a user, along with a channel whose slug is the lowercased user name. An existing user is replaced,
+// keeping its channel.
+func (s *Server) AddUser(user gokick.UserResponse) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.users[user.UserID] = user
+	if _, ok := s.channels[user.UserID]; !ok {
+		s.channels[user.UserID] = &gokick.ChannelResponse{
+			BroadcasterUserID: user.UserID,
+			Slug:              strings.ToLower(user.Name),
+		}
+	}
+}
+
+// SetChannel replaces the channel of channel.BroadcasterUserID.
+func (s *Server) SetChannel(channel gokick.ChannelResponse) {
+	s.mu.Lock()
+	s.channels[channel.BroadcasterUserID] = &channel
+	s.mu.Unlock()
+}
+
+// Channel returns the channel of broadcasterUserID.
+func (s *Server) Channel(broadcasterUserID int) (gokick.ChannelResponse, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	channel, ok := s.channels[broadcasterUserID]
+	if !ok {
+		return gokick.ChannelResponse{}, false
+	}
+
+	return *channel, true
+}
+
+// Rewards returns the channel rewards of broadcasterUserID.
+func (s *Server) Rewards(broadcasterUserID int) []gokick.ChannelRewardResponse {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rewards := make([]gokick.ChannelRewardResponse, 0, len(s.rewards[broadcasterUserID]))
+	for _, reward := range s.rewards[broadcasterUserID] {
+		rewards = append(rewards, *reward)
+	}
+
+	return rewards
+}
+
+func (s *Server) registerChannelRoutes() {
+	s.mux.HandleFunc("GET /public/v1/channels", s.handleGetChannels)
+	s.mux.HandleFunc("PATCH /public/v1/channels", s.handlePatchChannel)
+	s.mux.HandleFunc("GET /public/v1/channels/rewards", s.handleGetRewards)
+	s.mux.HandleFunc("POST /public/v1/channels/rewards", s.handleCreateReward)
+	s.mux.HandleFunc("PATCH /public/v1/channels/rewards/{id}", s.handleUpdateReward)
+	s.mux.HandleFunc("DELETE /public/v1/channels/rewards/{id}", s.handleDeleteReward)
+}
+
+// handleGetChannels returns the channels of the broadcaster_user_id or slug parameters, or the channel of the
+// user when there are none.
+func (s *Server) handleGetChannels(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	ids := query["broadcaster_user_id"]
+	slugs := query["slug"]
+
+	if len(ids) == 0 && len(slugs) == 0 {
+		userID, ok := userCaller(w, r)
+		if !ok {
+			return
+		}
+		ids = []string{strconv.Itoa(userID)}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	channels := []gokick.ChannelResponse{}
+	for _, value := range ids {
+		id, err := strconv.Atoi(value)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid broadcaster_user_id")
+			return
+		}
+		if channel, ok := s.channels[id]; ok {
+			channels = append(channels, *channel)
+		}
+	}
+	for _, channel := range s.channels {
+		if slices.Contains(slugs, channel.Slug) {
+			channels = append(channels, *channel)
+		}
+	}
+
+	writeData(w, http.StatusOK, channels)
+}
+
+func (s *Server) handlePatchChannel(w http.ResponseWriter, r *http.Request) {
+	userID, ok := userCaller(w, r)
+	if !ok {
+		return
+	}
+
+	var body struct {
+		CategoryID  *int     `json:"category_id"`
+		StreamTitle *string  `json:"stream_title"`
+		CustomTags  []string `json:"custom_tags"`
+	}
+	if !decodeBody(w, r, &body) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	channel := s.channels[userID]
+	if body.CategoryID != nil {
+		index := slices.IndexFunc(s.categories, func(category gokick.CategoryResponse) bool {
+			return category.ID == *body.CategoryID
+		})
+		if index < 0 {
+			writeError(w, http.StatusBadRequest, "Invalid category")
+			return
+		}
+		channel.Category = s.categories[index]
+	}
+	if body.StreamTitle != nil {
+		channel.StreamTitle = *body.StreamTitle
+	}
+	if body.CustomTags != nil {
+		channel.Stream.CustomTags = body.CustomTags
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) handleGetRewards(w http.ResponseWriter, r *http.Request) {
+	userID, ok := userCaller(w, r)
+	if !ok {
+		return
+	}
+
+	writeData(w, http.StatusOK, s.Rewards(userID))
+}
+
+func (s *Server) handleCreateReward(w http.ResponseWriter, r *http.Request) {
+	userID, ok := userCaller(w, r)
+	if !ok {
+		return
+	}
+
+	var body gokick.CreateChannelRewardRequest
+	if !decodeBody(w, r, &body) {
+		return
+	}
+
+	if body.Title == "" || body.Cost < 1 {
+		writeError(w, http.StatusBadRequest, "Title and a cost of at least 1 are required")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	reward := &gokick.ChannelRewardResponse{
+		BackgroundColor:                   valueOr(body.BackgroundColor, "#00e701"),
+		Cost:                              body.Cost,
+		Description:                       valueOr(body.Description, ""),
+		ID:                                s.newID("reward"),
+		IsEnabled:                         valueOr(body.IsEnabled, true),
+		IsPaused:                          valueOr(nil, false),
+		IsUserInputRequired:               valueOr(body.IsUserInputRequired, false),
+		ShouldRedemptionsSkipRequestQueue: valueOr(body.ShouldRedemptionsSkipRequestQueue, false),
+		Title:                             body.Title,
+	}
+	s.rewards[userID] = append(s.rewards[userID], reward)
+
+	writeData(w, http.StatusOK, reward)
+}
+
+func (s *Server) handleUpdateReward(w http.ResponseWriter, r *http.Request) {
+	userID, ok := userCaller(w, r)
+	if !ok {
+		return
+	}
+
+	var body gokick.UpdateChannelRewardRequest
+	if !decodeBody(w, r, &body) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	index := s.rewardIndex(userID, r.PathValue("id"))
+	if index < 0 {
+		writeError(w, http.StatusNotFound, "Reward not found")
+		return
+	}
+
+	reward := s.rewards[userID][index]
+	setIfPresent(&reward.BackgroundColor, body.BackgroundColor)
+	setIfPresent(&reward.Description, body.Description)
+	setIfPresent(&reward.IsEnabled, body.IsEnabled)
+	setIfPresent(&reward.IsPaused, body.IsPaused)
+	setIfPresent(&reward.IsUserInputRequired, body.IsUserInputRequired)
+	setIfPresent(&reward.ShouldRedemptionsSkipRequestQueue, body.ShouldRedemptionsSkipRequestQueue)
+	if body.Cost != nil {
+		reward.Cost = *body.Cost
+	}
+	if body.Title != nil {
+		reward.Title = *body.Title
+	}
+
+	writeData(w, http.StatusOK, reward)
+}
+
+func (s *Server) handleDeleteReward(w http.ResponseWriter, r *http.Request) {
+	userID, ok := userCaller(w, r)
+	if !ok {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	index := s.rewardIndex(userID, r.PathValue("id"))
+	if index < 0 {
+		writeError(w, http.StatusNotFound, "Reward not found")
+		return
+	}
+
+	s.rewards[userID] = slices.Delete(s.rewards[userID], index, index+1)
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// rewardIndex returns the index of the reward id of userID, or -1. s.mu must be held.
+func (s *Server) rewardIndex(userID int, id string) int {
+	return slices.IndexFunc(s.rewards[userID], func(reward *gokick.ChannelRewardResponse) bool {
+		return reward.ID == id
+	})
+}
+
+// valueOr returns a pointer to the value of value, or to fallback when value is nil.
+func valueOr[T any](value *T, fallback T) *T {
+	if value != nil {
+		fallback = *value
+	}
+
+	return &fallback
+}
+
+// setIfPresent replaces *field with a copy of value, when value is not nil.
+func setIfPresent[T any](field **T, value *T) {
+	if value != nil {
+		*field = valueOr(value, *value)
+	}
+}
@@ -0,0 +1,78 @@
+package gokicktest_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/scorfly/gokick"
+	"github.com/scorfly/gokick/gokicktest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChannels(t *testing.T) {
+	server := gokicktest.NewServer(t)
+	server.AddUser(gokick.UserResponse{UserID: 2, Name: "Other"})
+	client := server.Client()
+
+	_, err := client.UpdateStreamTitle(context.Background(), "Speedrun")
+	require.NoError(t, err)
+	_, err = client.UpdateStreamCategory(context.Background(), 2)
+	require.NoError(t, err)
+	_, err = client.UpdateStreamTags(context.Background(), []string{"english"})
+	require.NoError(t, err)
+
+	_, err = client.UpdateStreamCategory(context.Background(), 404)
+	require.ErrorIs(t, err, gokick.ErrValidation)
+
+	own, err := client.GetChannels(context.Background(), gokick.NewChannelListFilter())
+	require.NoError(t, err)
+	require.Len(t, own.Result, 1)
+	assert.Equal(t, "Speedrun", own.Result[0].StreamTitle)
+	assert.Equal(t, "Counter-Strike 2", own.Result[0].Category.Name)
+	assert.Equal(t, []string{"english"}, own.Result[0].Stream.CustomTags)
+
+	bySlug, err := client.GetChannels(context.Background(), gokick.NewChannelListFilter().SetSlug([]string{"other"}))
+	require.NoError(t, err)
+	require.Len(t, bySlug.Result, 1)
+	assert.Equal(t, 2, bySlug.Result[0].BroadcasterUserID)
+
+	byID, err := client.GetChannels(context.Background(), gokick.NewChannelListFilter().SetBroadcasterUserIDs([]int{1, 2, 3}))
+	require.NoError(t, err)
+	assert.Len(t, byID.Result, 2)
+}
+
+func TestChannelRewards(t *testing.T) {
+	server := gokicktest.NewServer(t)
+	client := server.Client()
+
+	created, err := client.CreateChannelReward(context.Background(), gokick.CreateChannelRewardRequest{Title: "Hydrate", Cost: 100})
+	require.NoError(t, err)
+	assert.Equal(t, "Hydrate", created.Result.Title)
+	require.NotNil(t, created.Result.IsEnabled)
+	assert.True(t, *created.Result.IsEnabled)
+
+	_, err = client.CreateChannelReward(context.Background(), gokick.CreateChannelRewardRequest{Title: "Free"})
+	require.ErrorIs(t, err, gokick.ErrValidation)
+
+	cost := 200
+	paused := true
+	updated, err := client.UpdateChannelReward(context.Background(), created.Result.ID,
+		gokick.UpdateChannelRewardRequest{Cost: &cost, IsPaused: &paused})
+	require.NoError(t, err)
+	assert.Equal(t, 200, updated.Result.Cost)
+	assert.True(t, *updated.Result.IsPaused)
+	assert.Equal(t, "Hydrate", updated.Result.Title)
+
+	rewards, err := client.GetChannelRewards(context.Background())
+	require.NoError(t, err)
+	require.Len(t, rewards.Result, 1)
+	assert.Equal(t, updated.Result, rewards.Result[0])
+
+	_, err = client.DeleteChannelReward(context.Background(), created.Result.ID)
+	require.NoError(t, err)
+	assert.Empty(t, server.Rewards(gokicktest.BroadcasterUserID))
+
+	_, err = client.DeleteChannelReward(context.Background(), created.Result.ID)
+	require.ErrorIs(t, err, gokick.ErrNotFound)
+}
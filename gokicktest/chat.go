@@ -0,0 +1,102 @@
+package gokicktest
+
+import (
+	"net/http"
+
+	"github.com/scorfly/gokick"
+)
+
+// ChatMessage is a chat message sent to the server.
+type ChatMessage struct {
+	ID                string
+	BroadcasterUserID int
+	// SenderUserID is the user of the access token the message was sent with, 0 for an app access token.
+	SenderUserID     int
+	Content          string
+	ReplyToMessageID string
+	Type             gokick.MessageType
+	Deleted          bool
+}
+
+// ChatMessages returns the chat messages sent so far, deleted ones included.
+func (s *Server) ChatMessages() []ChatMessage {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	messages := make([]ChatMessage, 0, len(s.messages))
+	for _, message := range s.messages {
+		messages = append(messages, *message)
+	}
+
+	return messages
+}
+
+func (s *Server) registerChatRoutes() {
+	s.mux.HandleFunc("POST /public/v1/chat", s.handleSendChatMessage)
+	s.mux.HandleFunc("DELETE /public/v1/chat/{message_id}", s.handleDeleteChatMessage)
+}
+
+// handleSendChatMessage posts a message. Messages of type "bot" go to the channel of the sender, and messages of
+// type "user" to the channel of broadcaster_user_id.
+func (s *Server) handleSendChatMessage(w http.ResponseWriter, r *http.Request) {
+	caller, _ := r.Context().Value(callerKey{}).(caller)
+
+	var body struct {
+		BroadcasterUserID int    `json:"broadcaster_user_id"`
+		Content           string `json:"content"`
+		ReplyToMessageID  string `json:"reply_to_message_id"`
+		Type              string `json:"type"`
+	}
+	if !decodeBody(w, r, &body) {
+		return
+	}
+
+	messageType, err := gokick.NewMessageType(body.Type)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid message type")
+		return
+	}
+	if messageType == gokick.MessageTypeBot {
+		body.BroadcasterUserID = caller.userID
+	}
+
+	if body.Content == "" || gokick.ValidateChatMessageContent(body.Content) != nil {
+		writeError(w, http.StatusBadRequest, "Content must be between 1 and 500 characters")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.channels[body.BroadcasterUserID]; !ok {
+		writeError(w, http.StatusNotFound, "Channel not found")
+		return
+	}
+
+	message := &ChatMessage{
+		ID:                s.newID("message"),
+		BroadcasterUserID: body.BroadcasterUserID,
+		SenderUserID:      caller.userID,
+		Content:           body.Content,
+		ReplyToMessageID:  body.ReplyToMessageID,
+		Type:              messageType,
+	}
+	s.messages = append(s.messages, message)
+
+	writeData(w, http.StatusOK, gokick.ChatResponse{IsSent: true, MessageID: message.ID})
+}
+
+func (s *Server) handleDeleteChatMessage(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, message := range s.messages {
+		if message.ID == r.PathValue("message_id") && !message.Deleted {
+			message.Deleted = true
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+	}
+
+	writeError(w, http.StatusNotFound, "Message not found")
+}
@@ -0,0 +1,51 @@
+package gokicktest_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/scorfly/gokick"
+	"github.com/scorfly/gokick/gokicktest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChat(t *testing.T) {
+	server := gokicktest.NewServer(t)
+	server.AddUser(gokick.UserResponse{UserID: 2, Name: "other"})
+	client := server.Client()
+
+	bot, err := client.SendChatMessage(context.Background(), nil, "hello", nil, gokick.MessageTypeBot)
+	require.NoError(t, err)
+	assert.True(t, bot.Result.IsSent)
+
+	other := 2
+	reply, err := client.SendChatMessage(context.Background(), &other, "hi", &bot.Result.MessageID, gokick.MessageTypeUser)
+	require.NoError(t, err)
+
+	_, err = client.DeleteChatMessage(context.Background(), reply.Result.MessageID)
+	require.NoError(t, err)
+
+	assert.Equal(t, []gokicktest.ChatMessage{
+		{
+			ID:                bot.Result.MessageID,
+			BroadcasterUserID: gokicktest.BroadcasterUserID,
+			SenderUserID:      gokicktest.BroadcasterUserID,
+			Content:           "hello",
+			Type:              gokick.MessageTypeBot,
+		},
+		{
+			ID:                reply.Result.MessageID,
+			BroadcasterUserID: 2,
+			SenderUserID:      gokicktest.BroadcasterUserID,
+			Content:           "hi",
+			ReplyToMessageID:  bot.Result.MessageID,
+			Type:              gokick.MessageTypeUser,
+			Deleted:           true,
+		},
+	}, server.ChatMessages())
+
+	unknown := 404
+	_, err = client.SendChatMessage(context.Background(), &unknown, "hello", nil, gokick.MessageTypeUser)
+	require.ErrorIs(t, err, gokick.ErrNotFound)
+}
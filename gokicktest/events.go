@@ -0,0 +1,96 @@
+package gokicktest
+
+import (
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/scorfly/gokick"
+)
+
+// Subscriptions returns the event subscriptions.
+func (s *Server) Subscriptions() []gokick.EventResponse {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]gokick.EventResponse{}, s.subscriptions...)
+}
+
+func (s *Server) registerEventRoutes() {
+	s.mux.HandleFunc("GET /public/v1/events/subscriptions", s.handleGetSubscriptions)
+	s.mux.HandleFunc("POST /public/v1/events/subscriptions", s.handleCreateSubscriptions)
+	s.mux.HandleFunc("DELETE /public/v1/events/subscriptions", s.handleDeleteSubscriptions)
+}
+
+func (s *Server) handleGetSubscriptions(w http.ResponseWriter, _ *http.Request) {
+	writeData(w, http.StatusOK, s.Subscriptions())
+}
+
+// handleCreateSubscriptions subscribes to events for broadcaster_user_id, or for the user when it is not set.
+// Unknown events are reported in the error field of their result.
+func (s *Server) handleCreateSubscriptions(w http.ResponseWriter, r *http.Request) {
+	caller, _ := r.Context().Value(callerKey{}).(caller)
+
+	var body struct {
+		Method string `json:"method"`
+		Events []struct {
+			Name    string `json:"name"`
+			Version int    `json:"version"`
+		} `json:"events"`
+		BroadcasterUserID int `json:"broadcaster_user_id"`
+	}
+	if !decodeBody(w, r, &body) {
+		return
+	}
+
+	if body.BroadcasterUserID == 0 {
+		body.BroadcasterUserID = caller.userID
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now().UTC().Format(time.RFC3339)
+	results := make([]gokick.CreateSubscriptionResponse, 0, len(body.Events))
+	for _, event := range body.Events {
+		result := gokick.CreateSubscriptionResponse{Name: event.Name, Version: event.Version}
+
+		_, err := gokick.NewSubscriptionName(event.Name)
+		if err != nil {
+			result.Error = "unknown event"
+			results = append(results, result)
+			continue
+		}
+
+		result.SubscriptionID = s.newID("subscription")
+		s.subscriptions = append(s.subscriptions, gokick.EventResponse{
+			AppID:             ClientID,
+			BroadcasterUserID: body.BroadcasterUserID,
+			CreatedAt:         now,
+			Event:             event.Name,
+			ID:                result.SubscriptionID,
+			Method:            body.Method,
+			UpdatedAt:         now,
+			Version:           event.Version,
+		})
+		results = append(results, result)
+	}
+
+	writeData(w, http.StatusOK, results)
+}
+
+func (s *Server) handleDeleteSubscriptions(w http.ResponseWriter, r *http.Request) {
+	ids := r.URL.Query()["id"]
+	if len(ids) == 0 {
+		writeError(w, http.StatusBadRequest, "At least one id is required")
+		return
+	}
+
+	s.mu.Lock()
+	s.subscriptions = slices.DeleteFunc(s.subscriptions, func(subscription gokick.EventResponse) bool {
+		return slices.Contains(ids, subscription.ID)
+	})
+	s.mu.Unlock()
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,39 @@
+package gokicktest_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/scorfly/gokick"
+	"github.com/scorfly/gokick/gokicktest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSubscriptions(t *testing.T) {
+	server := gokicktest.NewServer(t)
+	client := server.Client()
+
+	created, err := client.CreateSubscriptions(context.Background(), gokick.SubscriptionMethodWebhook, []gokick.SubscriptionRequest{
+		{Name: gokick.SubscriptionNameChatMessage, Version: 1},
+		{Name: gokick.SubscriptionNameChannelFollow, Version: 1},
+	}, nil)
+	require.NoError(t, err)
+	require.Len(t, created.Result, 2)
+	assert.Empty(t, created.Result[0].Error)
+
+	subscriptions, err := client.GetSubscriptions(context.Background())
+	require.NoError(t, err)
+	require.Len(t, subscriptions.Result, 2)
+	assert.Equal(t, "chat.message.sent", subscriptions.Result[0].Event)
+	assert.Equal(t, gokicktest.BroadcasterUserID, subscriptions.Result[0].BroadcasterUserID)
+	assert.Equal(t, "webhook", subscriptions.Result[0].Method)
+
+	_, err = client.DeleteSubscriptions(context.Background(),
+		gokick.NewSubscriptionToDeleteFilter().SetIDs([]string{created.Result[0].SubscriptionID}))
+	require.NoError(t, err)
+
+	remaining := server.Subscriptions()
+	require.Len(t, remaining, 1)
+	assert.Equal(t, created.Result[1].SubscriptionID, remaining[0].ID)
+}
@@ -0,0 +1,84 @@
+package gokicktest
+
+import (
+	"net/http"
+)
+
+// Ban is a ban of a user from a channel. Duration is in minutes, 0 for a permanent ban.
+type Ban struct {
+	BroadcasterUserID int
+	UserID            int
+	Duration          int
+	Reason            string
+}
+
+type banKey struct {
+	broadcasterUserID int
+	userID            int
+}
+
+// Ban returns the ban of userID from the channel of broadcasterUserID, if any.
+func (s *Server) Ban(broadcasterUserID, userID int) (Ban, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ban, ok := s.bans[banKey{broadcasterUserID: broadcasterUserID, userID: userID}]
+	return ban, ok
+}
+
+func (s *Server) registerModerationRoutes() {
+	s.mux.HandleFunc("POST /public/v1/moderation/bans", s.handleBan)
+	s.mux.HandleFunc("DELETE /public/v1/moderation/bans", s.handleUnban)
+}
+
+func (s *Server) handleBan(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		BroadcasterUserID int    `json:"broadcaster_user_id"`
+		Duration          int    `json:"duration"`
+		Reason            string `json:"reason"`
+		UserID            int    `json:"user_id"`
+	}
+	if !decodeBody(w, r, &body) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.channels[body.BroadcasterUserID]; !ok {
+		writeError(w, http.StatusNotFound, "Channel not found")
+		return
+	}
+
+	s.bans[banKey{broadcasterUserID: body.BroadcasterUserID, userID: body.UserID}] = Ban{
+		BroadcasterUserID: body.BroadcasterUserID,
+		UserID:            body.UserID,
+		Duration:          body.Duration,
+		Reason:            body.Reason,
+	}
+
+	writeData(w, http.StatusOK, struct{}{})
+}
+
+func (s *Server) handleUnban(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		BroadcasterUserID int `json:"broadcaster_user_id"`
+		UserID            int `json:"user_id"`
+	}
+	if !decodeBody(w, r, &body) {
+		return
+	}
+
+	key := banKey{broadcasterUserID: body.BroadcasterUserID, userID: body.UserID}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.bans[key]; !ok {
+		writeError(w, http.StatusNotFound, "User is not banned")
+		return
+	}
+	delete(s.bans, key)
+
+	writeData(w, http.StatusOK, struct{}{})
+}
@@ -0,0 +1,34 @@
+package gokicktest_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/scorfly/gokick"
+	"github.com/scorfly/gokick/gokicktest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestModerationBans(t *testing.T) {
+	server := gokicktest.NewServer(t)
+	client := server.Client()
+
+	duration := 10
+	reason := "spam"
+	_, err := client.BanUser(context.Background(), gokicktest.BroadcasterUserID, 42, &duration, &reason)
+	require.NoError(t, err)
+
+	ban, ok := server.Ban(gokicktest.BroadcasterUserID, 42)
+	require.True(t, ok)
+	assert.Equal(t, gokicktest.Ban{BroadcasterUserID: gokicktest.BroadcasterUserID, UserID: 42, Duration: 10, Reason: "spam"}, ban)
+
+	_, err = client.UnbanUser(context.Background(), gokicktest.BroadcasterUserID, 42)
+	require.NoError(t, err)
+
+	_, ok = server.Ban(gokicktest.BroadcasterUserID, 42)
+	assert.False(t, ok)
+
+	_, err = client.UnbanUser(context.Background(), gokicktest.BroadcasterUserID, 42)
+	require.ErrorIs(t, err, gokick.ErrNotFound)
+}
@@ -0,0 +1,241 @@
+package gokicktest
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/scorfly/gokick"
+)
+
+type token struct {
+	accessToken  string
+	refreshToken string
+	userID       int
+	scopes       gokick.ScopeSet
+	expiresAt    time.Time
+	revoked      bool
+}
+
+func (t *token) active() bool {
+	return !t.revoked && time.Now().Before(t.expiresAt)
+}
+
+type authorizationCode struct {
+	userID        int
+	scopes        gokick.ScopeSet
+	redirectURI   string
+	codeChallenge string
+}
+
+// allScopes returns every scope known to gokick, walking the enum until its String is unknown.
+func allScopes() []gokick.Scope {
+	var scopes []gokick.Scope
+	for scope := gokick.Scope(0); scope.String() != "unknown"; scope++ {
+		scopes = append(scopes, scope)
+	}
+
+	return scopes
+}
+
+// IssueUserToken issues a user token pair for userID with scopes, valid for two hours.
+func (s *Server) IssueUserToken(userID int, scopes ...gokick.Scope) gokick.Token {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.issueToken(userID, gokick.NewScopeSet(scopes...)).pair()
+}
+
+// UserToken returns the latest user token pair issued for the broadcaster by NewServer or by a refresh.
+func (s *Server) UserToken() gokick.Token {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.userToken
+}
+
+// ExpireAccessTokens expires every access token issued so far, user and app ones. The next API call of a client
+// fails with 401, then succeeds once the client refreshed its token.
+func (s *Server) ExpireAccessTokens() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, token := range s.tokens {
+		token.expiresAt = time.Now()
+	}
+}
+
+// issueToken issues an access token, along with a refresh token for user tokens. s.mu must be held.
+func (s *Server) issueToken(userID int, scopes gokick.ScopeSet) *token {
+	t := &token{
+		accessToken: s.newID("access-token"),
+		userID:      userID,
+		scopes:      scopes,
+		expiresAt:   time.Now().Add(tokenLifetime),
+	}
+	s.tokens[t.accessToken] = t
+
+	if userID != 0 {
+		t.refreshToken = s.newID("refresh-token")
+		s.refreshTokens[t.refreshToken] = t
+		if userID == BroadcasterUserID {
+			s.userToken = t.pair()
+		}
+	}
+
+	return t
+}
+
+func (t *token) pair() gokick.Token {
+	return gokick.Token{AccessToken: t.accessToken, RefreshToken: t.refreshToken, ExpiresAt: t.expiresAt}
+}
+
+func (t *token) response() gokick.TokenResponse {
+	return gokick.TokenResponse{
+		AccessToken:  t.accessToken,
+		TokenType:    "Bearer",
+		ExpiresIn:    int(tokenLifetime.Seconds()),
+		Scope:        t.scopes.String(),
+		RefreshToken: t.refreshToken,
+	}
+}
+
+func (s *Server) registerOAuthRoutes() {
+	s.mux.HandleFunc("GET /oauth/authorize", s.handleAuthorize)
+	s.mux.HandleFunc("POST /oauth/token", s.handleToken)
+	s.mux.HandleFunc("POST /oauth/revoke", s.handleRevoke)
+	s.mux.HandleFunc("POST /oauth/token/introspect", s.handleIntrospect)
+}
+
+// handleAuthorize grants the requested scopes for the broadcaster right away and redirects to the redirect URI
+// with the authorization code, as Kick does once the user approved the application.
+func (s *Server) handleAuthorize(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	if query.Get("client_id") != ClientID {
+		writeOAuthError(w, http.StatusBadRequest, "invalid_client", "unknown client ID")
+		return
+	}
+
+	redirectURI, err := url.Parse(query.Get("redirect_uri"))
+	if err != nil || redirectURI.Scheme == "" || query.Get("code_challenge") == "" {
+		writeOAuthError(w, http.StatusBadRequest, "invalid_request", "redirect URI and code challenge are required")
+		return
+	}
+
+	s.mu.Lock()
+	code := s.newID("code")
+	s.codes[code] = authorizationCode{
+		userID:        BroadcasterUserID,
+		scopes:        gokick.ParseScopeSet(query.Get("scope")),
+		redirectURI:   query.Get("redirect_uri"),
+		codeChallenge: query.Get("code_challenge"),
+	}
+	s.mu.Unlock()
+
+	callback := redirectURI.Query()
+	callback.Set("code", code)
+	callback.Set("state", query.Get("state"))
+	redirectURI.RawQuery = callback.Encode()
+
+	http.Redirect(w, r, redirectURI.String(), http.StatusFound)
+}
+
+func (s *Server) handleToken(w http.ResponseWriter, r *http.Request) {
+	err := r.ParseForm()
+	if err != nil {
+		writeOAuthError(w, http.StatusBadRequest, "invalid_request", "invalid form")
+		return
+	}
+
+	if r.PostForm.Get("client_id") != ClientID || r.PostForm.Get("client_secret") != ClientSecret {
+		writeOAuthError(w, http.StatusUnauthorized, "invalid_client", "invalid client credentials")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch r.PostForm.Get("grant_type") {
+	case "authorization_code":
+		code, ok := s.codes[r.PostForm.Get("code")]
+		delete(s.codes, r.PostForm.Get("code"))
+		if !ok || code.redirectURI != r.PostForm.Get("redirect_uri") ||
+			gokick.CodeChallengeS256(r.PostForm.Get("code_verifier")) != code.codeChallenge {
+			writeOAuthError(w, http.StatusBadRequest, "invalid_grant", "invalid authorization code")
+			return
+		}
+
+		writeJSON(w, http.StatusOK, s.issueToken(code.userID, code.scopes).response())
+	case "refresh_token":
+		previous, ok := s.refreshTokens[r.PostForm.Get("refresh_token")]
+		if !ok {
+			writeOAuthError(w, http.StatusBadRequest, "invalid_grant", "invalid refresh token")
+			return
+		}
+		delete(s.refreshTokens, previous.refreshToken)
+
+		writeJSON(w, http.StatusOK, s.issueToken(previous.userID, previous.scopes).response())
+	case "client_credentials":
+		t := s.issueToken(0, gokick.NewScopeSet())
+		writeJSON(w, http.StatusOK, gokick.AppTokenResponse{
+			AccessToken: t.accessToken,
+			TokenType:   "Bearer",
+			ExpiresIn:   int(tokenLifetime.Seconds()),
+		})
+	default:
+		writeOAuthError(w, http.StatusBadRequest, "unsupported_grant_type", "unsupported grant type")
+	}
+}
+
+func (s *Server) handleRevoke(w http.ResponseWriter, r *http.Request) {
+	err := r.ParseForm()
+	if err != nil {
+		writeOAuthError(w, http.StatusBadRequest, "invalid_request", "invalid form")
+		return
+	}
+
+	value := r.PostForm.Get("token")
+
+	s.mu.Lock()
+	if t, ok := s.refreshTokens[value]; ok {
+		delete(s.refreshTokens, value)
+		t.revoked = true
+	}
+	if t, ok := s.tokens[value]; ok {
+		t.revoked = true
+	}
+	s.mu.Unlock()
+
+	writeJSON(w, http.StatusOK, struct{}{})
+}
+
+func (s *Server) handleIntrospect(w http.ResponseWriter, r *http.Request) {
+	accessToken, _ := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t, ok := s.tokens[accessToken]
+	if !ok || !t.active() {
+		writeData(w, http.StatusOK, gokick.TokenIntrospectResponse{Active: false})
+		return
+	}
+
+	tokenType := "user"
+	if t.userID == 0 {
+		tokenType = "app"
+	}
+
+	writeData(w, http.StatusOK, gokick.TokenIntrospectResponse{
+		Active:    true,
+		ClientID:  ClientID,
+		Exp:       int(t.expiresAt.Unix()),
+		Scope:     t.scopes.String(),
+		TokenType: tokenType,
+	})
+}
+
+func writeOAuthError(w http.ResponseWriter, statusCode int, code, description string) {
+	writeJSON(w, statusCode, map[string]string{"error": code, "error_description": description})
+}
@@ -0,0 +1,85 @@
+package gokicktest_test
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/scorfly/gokick"
+	"github.com/scorfly/gokick/gokicktest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOAuthFlow(t *testing.T) {
+	server := gokicktest.NewServer(t)
+	client := server.NewClient(&gokick.ClientOptions{AppAccessToken: "unused"})
+
+	flow, err := gokick.NewOAuthFlow(client, gokick.OAuthFlowOptions{
+		RedirectURI: "http://localhost/callback",
+		Scopes:      []gokick.Scope{gokick.ScopeUserRead, gokick.ScopeChatWrite},
+	})
+	require.NoError(t, err)
+
+	authorizeURL, err := flow.AuthorizeURL("session")
+	require.NoError(t, err)
+
+	noRedirect := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }}
+	response, err := noRedirect.Get(authorizeURL)
+	require.NoError(t, err)
+	response.Body.Close()
+	require.Equal(t, http.StatusFound, response.StatusCode)
+
+	callback, err := url.Parse(response.Header.Get("Location"))
+	require.NoError(t, err)
+
+	token, err := flow.HandleCallback(context.Background(), "session", callback.Query())
+	require.NoError(t, err)
+	assert.Equal(t, "user:read chat:write", token.Scope)
+
+	userClient := server.NewClient(&gokick.ClientOptions{UserAccessToken: token.AccessToken})
+
+	introspection, err := userClient.TokenIntrospect(context.Background())
+	require.NoError(t, err)
+	assert.True(t, introspection.Result.Active)
+	assert.Equal(t, "user", introspection.Result.TokenType)
+	assert.Equal(t, gokicktest.ClientID, introspection.Result.ClientID)
+
+	_, err = flow.HandleCallback(context.Background(), "session", callback.Query())
+	require.ErrorIs(t, err, gokick.ErrValidation, "codes are single use")
+}
+
+func TestOAuthRefreshAndRevoke(t *testing.T) {
+	server := gokicktest.NewServer(t)
+	client := server.Client()
+	initial := server.UserToken()
+
+	refreshed, err := client.RefreshToken(context.Background(), initial.RefreshToken)
+	require.NoError(t, err)
+	assert.NotEqual(t, initial.AccessToken, refreshed.AccessToken)
+	assert.Equal(t, refreshed.AccessToken, server.UserToken().AccessToken)
+
+	_, err = client.RefreshToken(context.Background(), initial.RefreshToken)
+	require.ErrorIs(t, err, gokick.ErrValidation, "refresh tokens are rotated")
+
+	err = client.RevokeToken(context.Background(), gokick.TokenTypeAccess, refreshed.AccessToken)
+	require.NoError(t, err)
+
+	revokedClient := server.NewClient(&gokick.ClientOptions{UserAccessToken: refreshed.AccessToken})
+
+	introspection, err := revokedClient.TokenIntrospect(context.Background())
+	require.NoError(t, err)
+	assert.False(t, introspection.Result.Active)
+
+	_, err = revokedClient.GetUsers(context.Background(), gokick.NewUserListFilter())
+	require.ErrorIs(t, err, gokick.ErrUnauthorized)
+}
+
+func TestOAuthInvalidClient(t *testing.T) {
+	server := gokicktest.NewServer(t)
+	client := server.NewClient(&gokick.ClientOptions{ClientSecret: "wrong", AppAccessToken: "unused"})
+
+	_, err := client.GetAppAccessToken(context.Background())
+	require.ErrorIs(t, err, gokick.ErrUnauthorized)
+}
@@ -0,0 +1,389 @@
+// Package gokicktest provides a stateful in-memory fake of the Kick public API and OAuth server, to test code
+// using gokick without reaching Kick.
+//
+//	server := gokicktest.NewServer(t)
+//	client := server.Client()
+//
+//	_, err := client.UpdateStreamTitle(ctx, "New title")
+//	channel, _ := server.Channel(gokicktest.BroadcasterUserID)
+//	// channel.StreamTitle == "New title"
+//	server.AssertRequestCount(t, http.MethodPatch, "/public/v1/channels", 1)
+//
+// The fake covers channels, channel rewards, chat, moderation bans, event subscriptions, categories, users, and the
+// OAuth authorize, token, refresh, revoke and introspect endpoints. Failures can be injected with Fail and
+// ExpireAccessTokens.
+package gokicktest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/scorfly/gokick"
+)
+
+const (
+	// ClientID and ClientSecret are the application credentials accepted by the server.
+	ClientID     = "gokicktest-client-id"
+	ClientSecret = "gokicktest-client-secret"
+	// BroadcasterUserID is the user the tokens of Client are issued for.
+	BroadcasterUserID = 1
+
+	tokenLifetime = 2 * time.Hour
+)
+
+// Request is a request received by the server.
+type Request struct {
+	Method string
+	Path   string
+	Query  url.Values
+	Header http.Header
+	Body   []byte
+}
+
+// Failure makes the server answer matching requests with an error instead of handling them.
+type Failure struct {
+	// Method is the method of the requests to fail. Empty matches every method.
+	Method string
+	// Path is the endpoint path or route of the requests to fail, such as "/public/v1/chat/{message_id}".
+	// Empty matches every path.
+	Path string
+	// StatusCode is the status code answered, e.g. 429. Defaults to 500.
+	StatusCode int
+	// RetryAfter is sent in the Retry-After header, when set.
+	RetryAfter time.Duration
+	// Times is the number of requests failed. Defaults to 1.
+	Times int
+}
+
+// Server is a fake Kick server listening on a local address. It is closed when the test ends.
+type Server struct {
+	t      testing.TB
+	server *httptest.Server
+	mux    *http.ServeMux
+
+	mu            sync.Mutex
+	requests      []Request
+	failures      []*Failure
+	nextID        int
+	tokens        map[string]*token
+	refreshTokens map[string]*token
+	codes         map[string]authorizationCode
+	userToken     gokick.Token
+	users         map[int]gokick.UserResponse
+	channels      map[int]*gokick.ChannelResponse
+	categories    []gokick.CategoryResponse
+	rewards       map[int][]*gokick.ChannelRewardResponse
+	messages      []*ChatMessage
+	bans          map[banKey]Ban
+	subscriptions []gokick.EventResponse
+}
+
+// routeScopes lists the scopes a user token needs on each route. It is kept apart from gokick.RequiredScopes, so
+// that the fake does not agree with a mistake of the client.
+var routeScopes = map[string][]gokick.Scope{
+	"GET /public/v1/channels":                 {gokick.ScopeChannelRead},
+	"PATCH /public/v1/channels":               {gokick.ScopeChannelWrite},
+	"GET /public/v1/channels/rewards":         {gokick.ScopeChannelRewardsRead},
+	"POST /public/v1/channels/rewards":        {gokick.ScopeChannelRewardsWrite},
+	"PATCH /public/v1/channels/rewards/{id}":  {gokick.ScopeChannelRewardsWrite},
+	"DELETE /public/v1/channels/rewards/{id}": {gokick.ScopeChannelRewardsWrite},
+	"POST /public/v1/chat":                    {gokick.ScopeChatWrite},
+	"DELETE /public/v1/chat/{message_id}":     {gokick.ScopeModerationChatMessageManage},
+	"GET /public/v1/events/subscriptions":     {gokick.ScopeEventSubscribe},
+	"POST /public/v1/events/subscriptions":    {gokick.ScopeEventSubscribe},
+	"DELETE /public/v1/events/subscriptions":  {gokick.ScopeEventSubscribe},
+	"POST /public/v1/moderation/bans":         {gokick.ScopeModerationBan},
+	"DELETE /public/v1/moderation/bans":       {gokick.ScopeModerationBan},
+	"GET /public/v1/users":                    {gokick.ScopeUserRead},
+}
+
+type callerKey struct{}
+
+// caller is the owner of the access token a request is made with. userID is 0 for app access tokens.
+type caller struct {
+	userID int
+	scopes gokick.ScopeSet
+}
+
+// NewServer starts a fake server holding the broadcaster (user BroadcasterUserID, named "broadcaster") and a few
+// categories, and issues a user token with every scope for the broadcaster.
+func NewServer(t testing.TB) *Server {
+	t.Helper()
+
+	s := &Server{
+		t:             t,
+		mux:           http.NewServeMux(),
+		tokens:        make(map[string]*token),
+		refreshTokens: make(map[string]*token),
+		codes:         make(map[string]authorizationCode),
+		users:         make(map[int]gokick.UserResponse),
+		channels:      make(map[int]*gokick.ChannelResponse),
+		rewards:       make(map[int][]*gokick.ChannelRewardResponse),
+		bans:          make(map[banKey]Ban),
+	}
+
+	s.registerOAuthRoutes()
+	s.registerChannelRoutes()
+	s.registerChatRoutes()
+	s.registerModerationRoutes()
+	s.registerEventRoutes()
+	s.registerCategoryRoutes()
+	s.registerUserRoutes()
+
+	s.AddUser(gokick.UserResponse{UserID: BroadcasterUserID, Name: "broadcaster", Email: "broadcaster@example.com"})
+	s.AddCategory(gokick.CategoryResponse{ID: 1, Name: "Just Chatting", Tags: []string{"IRL"}})
+	s.AddCategory(gokick.CategoryResponse{ID: 2, Name: "Counter-Strike 2", Tags: []string{"FPS", "Shooter"}})
+	s.AddCategory(gokick.CategoryResponse{ID: 3, Name: "Slots & Casino", Tags: []string{"Gambling"}})
+	s.IssueUserToken(BroadcasterUserID, allScopes()...)
+
+	s.server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	t.Cleanup(s.server.Close)
+
+	return s
+}
+
+// URL returns the base URL of the server, to be used as both the APIBaseURL and the AuthBaseURL of a client.
+func (s *Server) URL() string {
+	return s.server.URL
+}
+
+// Client returns a client wired to the server, with the application credentials and the broadcaster's user
+// token: it can call every endpoint, refresh its user token and obtain app access tokens.
+func (s *Server) Client() *gokick.Client {
+	return s.NewClient(&gokick.ClientOptions{})
+}
+
+// NewClient returns a client built from options with its base URLs pointing to the server. The application
+// credentials are set when empty, as is the broadcaster's user token when options set no token at all.
+func (s *Server) NewClient(options *gokick.ClientOptions) *gokick.Client {
+	s.t.Helper()
+
+	options.APIBaseURL = s.server.URL
+	options.AuthBaseURL = s.server.URL
+	if options.ClientID == "" {
+		options.ClientID = ClientID
+	}
+	if options.ClientSecret == "" {
+		options.ClientSecret = ClientSecret
+	}
+	if options.UserAccessToken == "" && options.AppAccessToken == "" && !options.AutoAppAccessToken {
+		s.mu.Lock()
+		options.UserAccessToken = s.userToken.AccessToken
+		options.UserRefreshToken = s.userToken.RefreshToken
+		s.mu.Unlock()
+	}
+
+	client, err := gokick.NewClient(options)
+	if err != nil {
+		s.t.Fatalf("failed to create client: %v", err)
+	}
+
+	return client
+}
+
+// Fail injects a failure.
+func (s *Server) Fail(failure Failure) {
+	if failure.Times <= 0 {
+		failure.Times = 1
+	}
+	if failure.StatusCode == 0 {
+		failure.StatusCode = http.StatusInternalServerError
+	}
+
+	s.mu.Lock()
+	s.failures = append(s.failures, &failure)
+	s.mu.Unlock()
+}
+
+// Requests returns the requests received so far.
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]Request(nil), s.requests...)
+}
+
+// RequestCount returns the number of requests received with method on path, an endpoint path or route.
+func (s *Server) RequestCount(method, path string) int {
+	count := 0
+	for _, request := range s.Requests() {
+		if matches(method, path, request.Method, request.Path) {
+			count++
+		}
+	}
+
+	return count
+}
+
+// AssertRequested fails t when no request was received with method on path, an endpoint path or route.
+func (s *Server) AssertRequested(t testing.TB, method, path string) {
+	t.Helper()
+
+	if s.RequestCount(method, path) == 0 {
+		t.Errorf("gokicktest: expected a %s %s request, got none", method, path)
+	}
+}
+
+// AssertNotRequested fails t when a request was received with method on path, an endpoint path or route.
+func (s *Server) AssertNotRequested(t testing.TB, method, path string) {
+	t.Helper()
+
+	count := s.RequestCount(method, path)
+	if count != 0 {
+		t.Errorf("gokicktest: expected no %s %s request, got %d", method, path, count)
+	}
+}
+
+// AssertRequestCount fails t unless expected requests were received with method on path, an endpoint path or
+// route.
+func (s *Server) AssertRequestCount(t testing.TB, method, path string, expected int) {
+	t.Helper()
+
+	count := s.RequestCount(method, path)
+	if count != expected {
+		t.Errorf("gokicktest: expected %d %s %s requests, got %d", expected, method, path, count)
+	}
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "failed to read body")
+		return
+	}
+	r.Body = io.NopCloser(bytes.NewReader(body))
+
+	s.mu.Lock()
+	s.requests = append(s.requests, Request{
+		Method: r.Method,
+		Path:   r.URL.Path,
+		Query:  r.URL.Query(),
+		Header: r.Header.Clone(),
+		Body:   body,
+	})
+	failure := s.takeFailure(r)
+	s.mu.Unlock()
+
+	if failure != nil {
+		if failure.RetryAfter > 0 {
+			w.Header().Set("Retry-After", strconv.Itoa(int(failure.RetryAfter.Seconds())))
+		}
+		writeError(w, failure.StatusCode, http.StatusText(failure.StatusCode))
+		return
+	}
+
+	if strings.HasPrefix(r.URL.Path, "/public/") {
+		caller, ok := s.authenticate(w, r)
+		if !ok {
+			return
+		}
+		r = r.WithContext(context.WithValue(r.Context(), callerKey{}, caller))
+	}
+
+	s.mux.ServeHTTP(w, r)
+}
+
+// takeFailure returns the failure matching r, if any, and counts it. s.mu must be held.
+func (s *Server) takeFailure(r *http.Request) *Failure {
+	for i, failure := range s.failures {
+		if !matches(failure.Method, failure.Path, r.Method, r.URL.Path) {
+			continue
+		}
+
+		failure.Times--
+		if failure.Times == 0 {
+			s.failures = append(s.failures[:i], s.failures[i+1:]...)
+		}
+
+		return failure
+	}
+
+	return nil
+}
+
+// authenticate checks the access token of an API request and the scopes of the endpoint.
+func (s *Server) authenticate(w http.ResponseWriter, r *http.Request) (caller, bool) {
+	accessToken, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+
+	s.mu.Lock()
+	token, known := s.tokens[accessToken]
+	active := known && token.active()
+	var c caller
+	if active {
+		c = caller{userID: token.userID, scopes: token.scopes}
+	}
+	s.mu.Unlock()
+
+	if !ok || !active {
+		writeError(w, http.StatusUnauthorized, "Unauthenticated")
+		return caller{}, false
+	}
+
+	if c.userID != 0 {
+		_, route := s.mux.Handler(r)
+		missing := c.scopes.Missing(routeScopes[route]...)
+		if len(missing) > 0 {
+			writeError(w, http.StatusForbidden, fmt.Sprintf("Missing scope %s", missing[0]))
+			return caller{}, false
+		}
+	}
+
+	return c, true
+}
+
+// userCaller returns the user the request is made for, or answers 401 when it is made with an app access token.
+func userCaller(w http.ResponseWriter, r *http.Request) (int, bool) {
+	caller, _ := r.Context().Value(callerKey{}).(caller)
+	if caller.userID == 0 {
+		writeError(w, http.StatusUnauthorized, "A user access token is required")
+		return 0, false
+	}
+
+	return caller.userID, true
+}
+
+// newID returns a new identifier starting with prefix. s.mu must be held.
+func (s *Server) newID(prefix string) string {
+	s.nextID++
+	return fmt.Sprintf("%s-%d", prefix, s.nextID)
+}
+
+func matches(method, path, requestMethod, requestPath string) bool {
+	return (method == "" || method == requestMethod) &&
+		(path == "" || path == requestPath || path == gokick.Route(requestPath))
+}
+
+func decodeBody(w http.ResponseWriter, r *http.Request, value interface{}) bool {
+	err := json.NewDecoder(r.Body).Decode(value)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid body: %v", err))
+		return false
+	}
+
+	return true
+}
+
+func writeJSON(w http.ResponseWriter, statusCode int, value interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	_ = json.NewEncoder(w).Encode(value)
+}
+
+func writeData(w http.ResponseWriter, statusCode int, data interface{}) {
+	writeJSON(w, statusCode, map[string]interface{}{"message": "OK", "data": data})
+}
+
+func writeError(w http.ResponseWriter, statusCode int, message string) {
+	writeJSON(w, statusCode, map[string]interface{}{"message": message, "data": nil})
+}
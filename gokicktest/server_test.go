@@ -0,0 +1,160 @@
+package gokicktest_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/scorfly/gokick"
+	"github.com/scorfly/gokick/gokicktest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServerRecordsRequests(t *testing.T) {
+	server := gokicktest.NewServer(t)
+	client := server.Client()
+
+	_, err := client.DeleteChatMessage(context.Background(), "unknown")
+	require.ErrorIs(t, err, gokick.ErrNotFound)
+
+	server.AssertRequested(t, http.MethodDelete, "/public/v1/chat/{message_id}")
+	server.AssertRequestCount(t, http.MethodDelete, "/public/v1/chat/unknown", 1)
+	server.AssertNotRequested(t, http.MethodPost, "/public/v1/chat")
+
+	requests := server.Requests()
+	require.Len(t, requests, 1)
+	assert.Equal(t, "Bearer "+server.UserToken().AccessToken, requests[0].Header.Get("Authorization"))
+
+	recorder := &recordingTB{TB: t}
+	server.AssertRequestCount(recorder, http.MethodDelete, "", 2)
+	assert.Equal(t, []string{"gokicktest: expected 2 DELETE  requests, got 1"}, recorder.errors)
+}
+
+// recordingTB records the failures of the assertions instead of failing the test.
+type recordingTB struct {
+	testing.TB
+	errors []string
+}
+
+func (r *recordingTB) Errorf(format string, args ...interface{}) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func TestServerAuthentication(t *testing.T) {
+	server := gokicktest.NewServer(t)
+
+	t.Run("unknown token", func(t *testing.T) {
+		client := server.NewClient(&gokick.ClientOptions{UserAccessToken: "unknown"})
+
+		_, err := client.GetChannels(context.Background(), gokick.NewChannelListFilter())
+		require.ErrorIs(t, err, gokick.ErrUnauthorized)
+	})
+
+	t.Run("broadcaster token has every scope", func(t *testing.T) {
+		client := server.NewClient(&gokick.ClientOptions{})
+
+		introspection, err := client.TokenIntrospect(context.Background())
+		require.NoError(t, err)
+		scopes := introspection.Result.Scopes()
+		assert.True(t, scopes.HasAll(gokick.ScopeUserRead, gokick.ScopeKicksRead))
+		assert.Empty(t, scopes.Unknown())
+	})
+
+	t.Run("missing scope", func(t *testing.T) {
+		token := server.IssueUserToken(gokicktest.BroadcasterUserID, gokick.ScopeChannelRead)
+		client := server.NewClient(&gokick.ClientOptions{UserAccessToken: token.AccessToken})
+
+		_, err := client.GetChannels(context.Background(), gokick.NewChannelListFilter())
+		require.NoError(t, err)
+
+		_, err = client.UpdateStreamTitle(context.Background(), "title")
+		require.ErrorIs(t, err, gokick.ErrForbidden)
+
+		_, err = client.DeleteChatMessage(context.Background(), "message-id")
+		require.ErrorIs(t, err, gokick.ErrForbidden)
+	})
+
+	t.Run("app access token", func(t *testing.T) {
+		client := server.NewClient(&gokick.ClientOptions{AutoAppAccessToken: true})
+
+		users, err := client.GetUsers(context.Background(), gokick.NewUserListFilter().SetID(gokicktest.BroadcasterUserID))
+		require.NoError(t, err)
+		require.Len(t, users.Result, 1)
+		assert.Equal(t, "broadcaster", users.Result[0].Name)
+
+		_, err = client.GetChannelRewards(context.Background())
+		require.ErrorIs(t, err, gokick.ErrUnauthorized)
+	})
+}
+
+func TestServerFailures(t *testing.T) {
+	t.Run("rate limited then success", func(t *testing.T) {
+		server := gokicktest.NewServer(t)
+		server.Fail(gokicktest.Failure{
+			Method:     http.MethodGet,
+			Path:       "/public/v1/users",
+			StatusCode: http.StatusTooManyRequests,
+			RetryAfter: time.Second,
+		})
+
+		client := server.Client()
+
+		_, err := client.GetUsers(context.Background(), gokick.NewUserListFilter())
+		require.ErrorIs(t, err, gokick.ErrRateLimited)
+
+		var kickErr gokick.Error
+		require.ErrorAs(t, err, &kickErr)
+		assert.Equal(t, time.Second, kickErr.RetryAfter())
+
+		_, err = client.GetUsers(context.Background(), gokick.NewUserListFilter())
+		require.NoError(t, err)
+	})
+
+	t.Run("server errors retried", func(t *testing.T) {
+		server := gokicktest.NewServer(t)
+		server.Fail(gokicktest.Failure{StatusCode: http.StatusInternalServerError, Times: 2})
+
+		client := server.NewClient(&gokick.ClientOptions{
+			RetryPolicy: &gokick.RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond},
+		})
+
+		_, err := client.GetChannels(context.Background(), gokick.NewChannelListFilter())
+		require.NoError(t, err)
+		server.AssertRequestCount(t, http.MethodGet, "/public/v1/channels", 3)
+	})
+
+	t.Run("status code defaults to 500", func(t *testing.T) {
+		server := gokicktest.NewServer(t)
+		server.Fail(gokicktest.Failure{Path: "/public/v1/users"})
+
+		_, err := server.Client().GetUsers(context.Background(), gokick.NewUserListFilter())
+		require.ErrorIs(t, err, gokick.ErrServer)
+	})
+
+	t.Run("expired token refreshed", func(t *testing.T) {
+		server := gokicktest.NewServer(t)
+		client := server.Client()
+
+		refreshes := make(chan gokick.Token, 1)
+		client.OnUserAccessTokenRefreshed(func(accessToken, refreshToken string) {
+			refreshes <- gokick.Token{AccessToken: accessToken, RefreshToken: refreshToken}
+		})
+
+		server.ExpireAccessTokens()
+
+		_, err := client.UpdateStreamTitle(context.Background(), "After refresh")
+		require.NoError(t, err)
+
+		server.AssertRequestCount(t, http.MethodPatch, "/public/v1/channels", 2)
+		server.AssertRequestCount(t, http.MethodPost, "/oauth/token", 1)
+		refreshed := <-refreshes
+		assert.Equal(t, server.UserToken().AccessToken, refreshed.AccessToken)
+		assert.Equal(t, server.UserToken().RefreshToken, refreshed.RefreshToken)
+
+		channel, _ := server.Channel(gokicktest.BroadcasterUserID)
+		assert.Equal(t, "After refresh", channel.StreamTitle)
+	})
+}
@@ -0,0 +1,41 @@
+package gokicktest
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/scorfly/gokick"
+)
+
+func (s *Server) registerUserRoutes() {
+	s.mux.HandleFunc("GET /public/v1/users", s.handleGetUsers)
+}
+
+// handleGetUsers returns the users of the id parameters, or the user when there are none.
+func (s *Server) handleGetUsers(w http.ResponseWriter, r *http.Request) {
+	ids := r.URL.Query()["id"]
+	if len(ids) == 0 {
+		userID, ok := userCaller(w, r)
+		if !ok {
+			return
+		}
+		ids = []string{strconv.Itoa(userID)}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	users := make([]gokick.UserResponse, 0, len(ids))
+	for _, value := range ids {
+		id, err := strconv.Atoi(value)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid id")
+			return
+		}
+		if user, ok := s.users[id]; ok {
+			users = append(users, user)
+		}
+	}
+
+	writeData(w, http.StatusOK, users)
+}
@@ -14,11 +14,18 @@ import (
 func setupMockClient(t *testing.T, mockHandler http.HandlerFunc) *gokick.Client {
 	t.Helper()
 
+	return setupMockClientWithOptions(t, gokick.ClientOptions{UserAccessToken: "access-token"}, mockHandler)
+}
+
+// setupMockClientWithOptions mocks both the API (APIBaseURL) and id.kick.com (AuthBaseURL) for a client built from
+// options.
+func setupMockClientWithOptions(t *testing.T, options gokick.ClientOptions, mockHandler http.HandlerFunc) *gokick.Client {
+	t.Helper()
+
 	server := httptest.NewServer(mockHandler)
-	kickClient, err := gokick.NewClient(&gokick.ClientOptions{
-		UserAccessToken: "access-token",
-		APIBaseURL:      fmt.Sprintf("http://%s", server.Listener.Addr()),
-	})
+	options.APIBaseURL = fmt.Sprintf("http://%s", server.Listener.Addr())
+	options.AuthBaseURL = options.APIBaseURL
+	kickClient, err := gokick.NewClient(&options)
 	require.NoError(t, err)
 
 	t.Cleanup(func() { server.Close() })
@@ -0,0 +1,170 @@
+package gokick
+
+import (
+	"context"
+	"crypto/rsa"
+	"fmt"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+const defaultMinKeyRefreshInterval = time.Minute
+
+// KeyProvider provides the public key verifying the signature of webhook events. Implementations must be safe for
+// concurrent use.
+type KeyProvider interface {
+	// PublicKey returns the current key.
+	PublicKey(ctx context.Context) (*rsa.PublicKey, error)
+	// Refresh is called when a signature does not match the current key, and returns the key to try again with.
+	// The verification is retried once, when the key returned differs from the current one.
+	Refresh(ctx context.Context) (*rsa.PublicKey, error)
+}
+
+// StaticKeyProvider is a KeyProvider always returning the same key.
+type StaticKeyProvider struct {
+	publicKey *rsa.PublicKey
+}
+
+func NewStaticKeyProvider(publicKey *rsa.PublicKey) *StaticKeyProvider {
+	return &StaticKeyProvider{publicKey: publicKey}
+}
+
+func (p *StaticKeyProvider) PublicKey(_ context.Context) (*rsa.PublicKey, error) {
+	return p.publicKey, nil
+}
+
+func (p *StaticKeyProvider) Refresh(_ context.Context) (*rsa.PublicKey, error) {
+	return p.publicKey, nil
+}
+
+// FetchingKeyProviderOptions configures a FetchingKeyProvider.
+type FetchingKeyProviderOptions struct {
+	// MinRefreshInterval is the minimum time between two fetches of the key, successful or not, so that invalid
+	// signatures or a failing API cannot make the provider call the API on every event. Defaults to 1 minute.
+	MinRefreshInterval time.Duration
+}
+
+// FetchingKeyProvider is a KeyProvider fetching the key with GetPublicKey. The key is fetched on first use and
+// cached, then fetched again when a signature does not match it, so that a rotation of the key by Kick is picked
+// up without a restart.
+type FetchingKeyProvider struct {
+	client             *Client
+	minRefreshInterval time.Duration
+	now                func() time.Time
+	fetchGroup         singleflight.Group
+	mu                 sync.Mutex
+	publicKey          *rsa.PublicKey
+	fetchErr           error
+	fetchedAt          time.Time
+}
+
+func NewFetchingKeyProvider(client *Client, options FetchingKeyProviderOptions) *FetchingKeyProvider {
+	if options.MinRefreshInterval <= 0 {
+		options.MinRefreshInterval = defaultMinKeyRefreshInterval
+	}
+
+	return &FetchingKeyProvider{
+		client:             client,
+		minRefreshInterval: options.MinRefreshInterval,
+		now:                time.Now,
+	}
+}
+
+// PublicKey returns the cached key, fetching it when it has not been fetched yet. After a failed fetch, the error
+// is returned until the minimum refresh interval is over.
+func (p *FetchingKeyProvider) PublicKey(ctx context.Context) (*rsa.PublicKey, error) {
+	p.mu.Lock()
+	publicKey := p.publicKey
+	p.mu.Unlock()
+
+	if publicKey != nil {
+		return publicKey, nil
+	}
+
+	return p.Refresh(ctx)
+}
+
+// Refresh fetches the key again, bypassing the ResponseCache of the client, unless a fetch was attempted less than
+// the minimum refresh interval ago. The cached key is kept when the fetch fails. Concurrent calls share a single
+// fetch, during which PublicKey keeps returning the cached key.
+func (p *FetchingKeyProvider) Refresh(ctx context.Context) (*rsa.PublicKey, error) {
+	p.mu.Lock()
+	publicKey, fetchErr, fetchedAt := p.publicKey, p.fetchErr, p.fetchedAt
+	p.mu.Unlock()
+
+	if !fetchedAt.IsZero() && p.now().Sub(fetchedAt) < p.minRefreshInterval {
+		if publicKey == nil {
+			return nil, fetchErr
+		}
+
+		return publicKey, nil
+	}
+
+	result := p.fetchGroup.DoChan("public-key", func() (interface{}, error) {
+		return p.fetch(context.WithoutCancel(ctx))
+	})
+
+	select {
+	case r := <-result:
+		if r.Err != nil {
+			return nil, r.Err
+		}
+
+		return r.Val.(*rsa.PublicKey), nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// fetch gets the key from the API and caches it, recording the time of the attempt whatever its outcome.
+func (p *FetchingKeyProvider) fetch(ctx context.Context) (*rsa.PublicKey, error) {
+	if p.client.options.Cache != nil {
+		p.client.options.Cache.Invalidate("/public/v1/public-key")
+	}
+
+	publicKey, err := p.fetchPublicKey(ctx)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.fetchedAt = p.now()
+	p.fetchErr = err
+	if err != nil {
+		return nil, err
+	}
+	p.publicKey = publicKey
+
+	return publicKey, nil
+}
+
+func (p *FetchingKeyProvider) fetchPublicKey(ctx context.Context) (*rsa.PublicKey, error) {
+	response, err := p.client.GetPublicKey(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch public key: %w", err)
+	}
+
+	publicKey, err := ParsePublicKey([]byte(response.Result.PublicKey))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse fetched public key: %w", err)
+	}
+
+	return publicKey, nil
+}
+
+// defaultKeyProvider provides DefaultEventPublicKey, parsed on every call as it may be changed at any time.
+type defaultKeyProvider struct{}
+
+func (defaultKeyProvider) PublicKey(_ context.Context) (*rsa.PublicKey, error) {
+	publicKey, err := ParsePublicKey([]byte(DefaultEventPublicKey))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse public key: %w", err)
+	}
+
+	return publicKey, nil
+}
+
+func (p defaultKeyProvider) Refresh(ctx context.Context) (*rsa.PublicKey, error) {
+	return p.PublicKey(ctx)
+}
@@ -0,0 +1,258 @@
+package gokick_test
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/scorfly/gokick"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func generateKey(t *testing.T) *rsa.PrivateKey {
+	t.Helper()
+
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	return privateKey
+}
+
+func publicKeyPEM(t *testing.T, privateKey *rsa.PrivateKey) string {
+	t.Helper()
+
+	der, err := x509.MarshalPKIXPublicKey(&privateKey.PublicKey)
+	require.NoError(t, err)
+
+	return string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+}
+
+// newSignedWebhookRequest returns a webhook request signed with privateKey.
+func newSignedWebhookRequest(t *testing.T, privateKey *rsa.PrivateKey, body string) *http.Request {
+	t.Helper()
+
+	req := newWebhookRequest(t, "channel.followed", "1", body)
+
+	signature, err := gokick.NewWebhookSigner(privateKey).Sign(
+		req.Header.Get("Kick-Event-Message-Id"), req.Header.Get("Kick-Event-Message-Timestamp"), []byte(body))
+	require.NoError(t, err)
+	req.Header.Set("Kick-Event-Signature", signature)
+
+	return req
+}
+
+// setupPublicKeyServer serves the PEM of the key returned by current, and counts the calls.
+func setupPublicKeyServer(t *testing.T, cache *gokick.ResponseCache, current func() string) (*gokick.Client, *atomic.Int32) {
+	t.Helper()
+
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		calls.Add(1)
+
+		body, err := json.Marshal(map[string]interface{}{"data": gokick.PublicKeyResponse{PublicKey: current()}})
+		assert.NoError(t, err)
+		_, _ = w.Write(body)
+	}))
+	t.Cleanup(server.Close)
+
+	kickClient, err := gokick.NewClient(&gokick.ClientOptions{
+		AppAccessToken: "access-token",
+		APIBaseURL:     server.URL,
+		Cache:          cache,
+	})
+	require.NoError(t, err)
+
+	return kickClient, &calls
+}
+
+func TestParsePublicKey(t *testing.T) {
+	privateKey := generateKey(t)
+
+	publicKey, err := gokick.ParsePublicKey([]byte(publicKeyPEM(t, privateKey)))
+	require.NoError(t, err)
+	assert.True(t, publicKey.Equal(&privateKey.PublicKey))
+
+	_, err = gokick.ParsePublicKey([]byte("invalid key"))
+	require.EqualError(t, err, "failed to decode public key")
+}
+
+func TestStaticKeyProvider(t *testing.T) {
+	privateKey := generateKey(t)
+	provider := gokick.NewStaticKeyProvider(&privateKey.PublicKey)
+
+	handler := gokick.NewEventHandler()
+	handler.SetVerifier(newTestVerifier(t, gokick.WebhookVerifierOptions{KeyProvider: provider}))
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, newSignedWebhookRequest(t, privateKey, "{}"))
+	assert.Equal(t, http.StatusOK, recorder.Code)
+
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, newSignedWebhookRequest(t, generateKey(t), "{}"))
+	assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+
+	handler.SetVerifier(nil)
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, newSignedWebhookRequest(t, privateKey, "{}"))
+	assert.Equal(t, http.StatusUnauthorized, recorder.Code, "DefaultEventPublicKey is used again")
+}
+
+func TestFetchingKeyProviderRotation(t *testing.T) {
+	for _, withCache := range []bool{false, true} {
+		t.Run(fmt.Sprintf("with cache %t", withCache), func(t *testing.T) {
+			oldKey, newKey := generateKey(t), generateKey(t)
+
+			var current atomic.Value
+			current.Store(publicKeyPEM(t, oldKey))
+
+			var cache *gokick.ResponseCache
+			if withCache {
+				cache = gokick.NewResponseCache(gokick.ResponseCacheOptions{})
+			}
+			kickClient, calls := setupPublicKeyServer(t, cache, func() string { return current.Load().(string) })
+
+			handler := gokick.NewEventHandler()
+			handler.SetVerifier(newTestVerifier(t, gokick.WebhookVerifierOptions{
+				KeyProvider: gokick.NewFetchingKeyProvider(kickClient, gokick.FetchingKeyProviderOptions{
+					MinRefreshInterval: time.Nanosecond,
+				}),
+			}))
+
+			for range 2 {
+				recorder := httptest.NewRecorder()
+				handler.ServeHTTP(recorder, newSignedWebhookRequest(t, oldKey, "{}"))
+				assert.Equal(t, http.StatusOK, recorder.Code)
+			}
+			assert.Equal(t, int32(1), calls.Load(), "the key is fetched once")
+
+			current.Store(publicKeyPEM(t, newKey))
+
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, newSignedWebhookRequest(t, newKey, "{}"))
+			assert.Equal(t, http.StatusOK, recorder.Code, "the rotated key is fetched and the verification retried")
+			assert.Equal(t, int32(2), calls.Load())
+
+			recorder = httptest.NewRecorder()
+			handler.ServeHTTP(recorder, newSignedWebhookRequest(t, oldKey, "{}"))
+			assert.Equal(t, http.StatusUnauthorized, recorder.Code, "the previous key is no longer accepted")
+		})
+	}
+}
+
+func TestFetchingKeyProviderMinRefreshInterval(t *testing.T) {
+	privateKey := generateKey(t)
+	kickClient, calls := setupPublicKeyServer(t, nil, func() string { return publicKeyPEM(t, privateKey) })
+
+	handler := gokick.NewEventHandler()
+	handler.SetVerifier(newTestVerifier(t, gokick.WebhookVerifierOptions{
+		KeyProvider: gokick.NewFetchingKeyProvider(kickClient, gokick.FetchingKeyProviderOptions{}),
+	}))
+
+	for range 3 {
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, newSignedWebhookRequest(t, generateKey(t), "{}"))
+		assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+	}
+	assert.Equal(t, int32(1), calls.Load(), "invalid signatures do not refetch the key within the minimum interval")
+}
+
+func TestFetchingKeyProviderError(t *testing.T) {
+	t.Run("fetch", func(t *testing.T) {
+		kickClient := setupMockClient(t, func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+			fmt.Fprint(w, `{"message":"internal server error"}`)
+		})
+		provider := gokick.NewFetchingKeyProvider(kickClient, gokick.FetchingKeyProviderOptions{})
+
+		_, err := provider.PublicKey(context.Background())
+		require.ErrorIs(t, err, gokick.ErrServer)
+		assert.True(t, strings.HasPrefix(err.Error(), "failed to fetch public key: "))
+	})
+
+	t.Run("invalid key", func(t *testing.T) {
+		kickClient := setupMockClient(t, func(w http.ResponseWriter, _ *http.Request) {
+			fmt.Fprint(w, `{"data":{"public_key":"invalid key"}}`)
+		})
+		provider := gokick.NewFetchingKeyProvider(kickClient, gokick.FetchingKeyProviderOptions{})
+
+		_, err := provider.Refresh(context.Background())
+		require.EqualError(t, err, "failed to parse fetched public key: failed to decode public key")
+	})
+}
+
+func TestFetchingKeyProviderFailingAPI(t *testing.T) {
+	var calls atomic.Int32
+	kickClient := setupMockClient(t, func(w http.ResponseWriter, _ *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusInternalServerError)
+		fmt.Fprint(w, `{"message":"internal server error"}`)
+	})
+
+	now := time.Now()
+	provider := gokick.NewFetchingKeyProvider(kickClient, gokick.FetchingKeyProviderOptions{})
+	gokick.SetFetchingKeyProviderNow(provider, func() time.Time { return now })
+
+	for range 3 {
+		_, err := provider.PublicKey(context.Background())
+		require.ErrorIs(t, err, gokick.ErrServer)
+
+		_, err = provider.Refresh(context.Background())
+		require.ErrorIs(t, err, gokick.ErrServer)
+	}
+	assert.Equal(t, int32(1), calls.Load(), "failed fetches are not attempted again within the minimum interval")
+
+	now = now.Add(time.Minute)
+	_, err := provider.Refresh(context.Background())
+	require.ErrorIs(t, err, gokick.ErrServer)
+	assert.Equal(t, int32(2), calls.Load())
+}
+
+func TestFetchingKeyProviderRefreshDoesNotBlockReaders(t *testing.T) {
+	privateKey := generateKey(t)
+	release := make(chan struct{})
+	var calls atomic.Int32
+	kickClient := setupMockClient(t, func(w http.ResponseWriter, _ *http.Request) {
+		if calls.Add(1) > 1 {
+			<-release
+		}
+		fmt.Fprintf(w, `{"data":{"public_key":%q}}`, publicKeyPEM(t, privateKey))
+	})
+
+	var now atomic.Int64
+	now.Store(time.Now().UnixNano())
+	provider := gokick.NewFetchingKeyProvider(kickClient, gokick.FetchingKeyProviderOptions{})
+	gokick.SetFetchingKeyProviderNow(provider, func() time.Time { return time.Unix(0, now.Load()) })
+
+	_, err := provider.PublicKey(context.Background())
+	require.NoError(t, err)
+
+	now.Add(int64(time.Minute))
+	refreshed := make(chan error, 2)
+	for range 2 {
+		go func() {
+			_, err := provider.Refresh(context.Background())
+			refreshed <- err
+		}()
+	}
+	require.Eventually(t, func() bool { return calls.Load() == 2 }, time.Second, time.Millisecond)
+
+	publicKey, err := provider.PublicKey(context.Background())
+	require.NoError(t, err)
+	assert.True(t, publicKey.Equal(&privateKey.PublicKey), "the cached key is returned during the refresh")
+
+	close(release)
+	require.NoError(t, <-refreshed)
+	require.NoError(t, <-refreshed)
+	assert.Equal(t, int32(2), calls.Load(), "concurrent refreshes share a single fetch")
+}
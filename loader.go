@@ -0,0 +1,193 @@
+package gokick
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+const (
+	defaultLoaderWait         = 5 * time.Millisecond
+	defaultLoaderMaxBatchSize = 50
+)
+
+// LoaderOptions configures a UserLoader or a ChannelLoader.
+type LoaderOptions struct {
+	// Wait is how long IDs are collected before the batch call is made. Defaults to 5 milliseconds.
+	Wait time.Duration
+	// MaxBatchSize is the number of IDs sent in a single call, the API limit by default (50). A batch reaching it
+	// is sent right away, and the next IDs start a new batch.
+	MaxBatchSize int
+}
+
+// UserLoader batches the GetUsers calls of concurrent callers asking for a single user.
+type UserLoader struct {
+	loader *batchLoader[UserResponse]
+}
+
+// NewUserLoader returns a UserLoader making its calls with client.
+func NewUserLoader(client *Client, options LoaderOptions) *UserLoader {
+	return &UserLoader{loader: newBatchLoader(options, func(ctx context.Context, ids []int) (map[int]UserResponse, error) {
+		response, err := client.GetUsers(ctx, NewUserListFilter().SetIDs(ids))
+		if err != nil {
+			return nil, err
+		}
+
+		users := make(map[int]UserResponse, len(response.Result))
+		for _, user := range response.Result {
+			users[user.UserID] = user
+		}
+
+		return users, nil
+	})}
+}
+
+// Load returns the user userID, fetched along with the users requested by other callers within the wait window.
+// It returns an error matching ErrNotFound when Kick does not know the user.
+func (l *UserLoader) Load(ctx context.Context, userID int) (UserResponse, error) {
+	user, err := l.loader.load(ctx, userID)
+	if err != nil {
+		return UserResponse{}, fmt.Errorf("failed to load user %d: %w", userID, err)
+	}
+
+	return user, nil
+}
+
+// ChannelLoader batches the GetChannels calls of concurrent callers asking for a single channel.
+type ChannelLoader struct {
+	loader *batchLoader[ChannelResponse]
+}
+
+// NewChannelLoader returns a ChannelLoader making its calls with client.
+func NewChannelLoader(client *Client, options LoaderOptions) *ChannelLoader {
+	return &ChannelLoader{loader: newBatchLoader(options, func(ctx context.Context, ids []int) (map[int]ChannelResponse, error) {
+		response, err := client.GetChannels(ctx, NewChannelListFilter().SetBroadcasterUserIDs(ids))
+		if err != nil {
+			return nil, err
+		}
+
+		channels := make(map[int]ChannelResponse, len(response.Result))
+		for _, channel := range response.Result {
+			channels[channel.BroadcasterUserID] = channel
+		}
+
+		return channels, nil
+	})}
+}
+
+// Load returns the channel of broadcasterUserID, fetched along with the channels requested by other callers within
+// the wait window. It returns an error matching ErrNotFound when Kick does not know the channel.
+func (l *ChannelLoader) Load(ctx context.Context, broadcasterUserID int) (ChannelResponse, error) {
+	channel, err := l.loader.load(ctx, broadcasterUserID)
+	if err != nil {
+		return ChannelResponse{}, fmt.Errorf("failed to load channel %d: %w", broadcasterUserID, err)
+	}
+
+	return channel, nil
+}
+
+// batchLoader collects the IDs requested by concurrent callers and fetches them with a single call per batch.
+//
+// Callers are only batched with callers selecting the same token (WithAccessToken, WithUserAccessToken or
+// WithAppAccessToken). The call carries that selection but none of the other values of the callers' contexts,
+// such as their Trace, and is not canceled by them; each caller stops waiting when its own context is done.
+type batchLoader[V any] struct {
+	fetch        func(ctx context.Context, ids []int) (map[int]V, error)
+	wait         time.Duration
+	maxBatchSize int
+	mu           sync.Mutex
+	batches      map[tokenSelection]*loaderBatch[V]
+}
+
+type loaderBatch[V any] struct {
+	selection  tokenSelection
+	ids        []int
+	requested  map[int]bool
+	dispatched bool
+	done       chan struct{}
+	results    map[int]V
+	err        error
+}
+
+func newBatchLoader[V any](options LoaderOptions, fetch func(ctx context.Context, ids []int) (map[int]V, error)) *batchLoader[V] {
+	if options.Wait <= 0 {
+		options.Wait = defaultLoaderWait
+	}
+	if options.MaxBatchSize <= 0 {
+		options.MaxBatchSize = defaultLoaderMaxBatchSize
+	}
+
+	return &batchLoader[V]{
+		fetch:        fetch,
+		wait:         options.Wait,
+		maxBatchSize: options.MaxBatchSize,
+		batches:      make(map[tokenSelection]*loaderBatch[V]),
+	}
+}
+
+func (l *batchLoader[V]) load(ctx context.Context, id int) (V, error) {
+	selection := contextTokenSelection(ctx)
+
+	l.mu.Lock()
+	batch := l.batches[selection]
+	if batch == nil {
+		batch = &loaderBatch[V]{
+			selection: selection,
+			requested: make(map[int]bool),
+			done:      make(chan struct{}),
+		}
+		l.batches[selection] = batch
+		time.AfterFunc(l.wait, func() { l.dispatch(batch) })
+	}
+
+	if !batch.requested[id] {
+		batch.requested[id] = true
+		batch.ids = append(batch.ids, id)
+	}
+
+	full := len(batch.ids) >= l.maxBatchSize
+	if full {
+		delete(l.batches, selection)
+	}
+	l.mu.Unlock()
+
+	if full {
+		go l.dispatch(batch)
+	}
+
+	var zero V
+	select {
+	case <-batch.done:
+	case <-ctx.Done():
+		return zero, ctx.Err()
+	}
+
+	if batch.err != nil {
+		return zero, batch.err
+	}
+
+	value, ok := batch.results[id]
+	if !ok {
+		return zero, ErrNotFound
+	}
+
+	return value, nil
+}
+
+// dispatch fetches the IDs of batch, once, and wakes up its callers.
+func (l *batchLoader[V]) dispatch(batch *loaderBatch[V]) {
+	l.mu.Lock()
+	if batch.dispatched {
+		l.mu.Unlock()
+		return
+	}
+	batch.dispatched = true
+	if l.batches[batch.selection] == batch {
+		delete(l.batches, batch.selection)
+	}
+	l.mu.Unlock()
+
+	batch.results, batch.err = l.fetch(withTokenSelection(context.Background(), batch.selection), batch.ids)
+	close(batch.done)
+}
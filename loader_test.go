@@ -0,0 +1,177 @@
+package gokick_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/scorfly/gokick"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupLoaderServer answers GetUsers and GetChannels with one entry per requested ID, except ID 404, and records
+// the IDs of each call.
+func setupLoaderServer(t *testing.T) (*gokick.Client, func() [][]int) {
+	t.Helper()
+
+	var mu sync.Mutex
+	var calls [][]int
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		param, template := "id", `{"user_id": %d, "name": "user %d"}`
+		if r.URL.Path == "/public/v1/channels" {
+			param, template = "broadcaster_user_id", `{"broadcaster_user_id": %d, "slug": "channel-%d"}`
+		}
+
+		var ids []int
+		var entries []string
+		for _, value := range r.URL.Query()[param] {
+			id, err := strconv.Atoi(value)
+			require.NoError(t, err)
+
+			ids = append(ids, id)
+			if id != 404 {
+				entries = append(entries, fmt.Sprintf(template, id, id))
+			}
+		}
+
+		mu.Lock()
+		calls = append(calls, ids)
+		mu.Unlock()
+
+		fmt.Fprintf(w, `{"message":"success", "data":[%s]}`, strings.Join(entries, ","))
+	}))
+	t.Cleanup(server.Close)
+
+	kickClient, err := gokick.NewClient(&gokick.ClientOptions{
+		AppAccessToken: "access-token",
+		APIBaseURL:     server.URL,
+	})
+	require.NoError(t, err)
+
+	return kickClient, func() [][]int {
+		mu.Lock()
+		defer mu.Unlock()
+
+		return calls
+	}
+}
+
+func TestUserLoader(t *testing.T) {
+	kickClient, calls := setupLoaderServer(t)
+	loader := gokick.NewUserLoader(kickClient, gokick.LoaderOptions{Wait: 20 * time.Millisecond})
+
+	ids := []int{1, 2, 3, 2, 404}
+	users := make([]gokick.UserResponse, len(ids))
+	errs := make([]error, len(ids))
+
+	var wg sync.WaitGroup
+	for i, id := range ids {
+		wg.Go(func() {
+			users[i], errs[i] = loader.Load(context.Background(), id)
+		})
+	}
+	wg.Wait()
+
+	for i, id := range ids[:4] {
+		require.NoError(t, errs[i])
+		assert.Equal(t, id, users[i].UserID)
+		assert.Equal(t, fmt.Sprintf("user %d", id), users[i].Name)
+	}
+	require.ErrorIs(t, errs[4], gokick.ErrNotFound)
+	assert.EqualError(t, errs[4], "failed to load user 404: gokick: not found")
+
+	require.Len(t, calls(), 1)
+	assert.ElementsMatch(t, []int{1, 2, 3, 404}, calls()[0])
+}
+
+func TestChannelLoaderSplitsBatches(t *testing.T) {
+	kickClient, calls := setupLoaderServer(t)
+	loader := gokick.NewChannelLoader(kickClient, gokick.LoaderOptions{Wait: time.Second})
+
+	start := time.Now()
+
+	var wg sync.WaitGroup
+	for id := 1; id <= 120; id++ {
+		wg.Go(func() {
+			channel, err := loader.Load(context.Background(), id)
+			assert.NoError(t, err)
+			assert.Equal(t, fmt.Sprintf("channel-%d", id), channel.Slug)
+		})
+	}
+	wg.Wait()
+
+	var sizes []int
+	for _, ids := range calls() {
+		sizes = append(sizes, len(ids))
+	}
+	assert.ElementsMatch(t, []int{50, 50, 20}, sizes)
+	assert.GreaterOrEqual(t, time.Since(start), time.Second, "the last batch waits for the window to close")
+}
+
+func TestLoaderError(t *testing.T) {
+	kickClient := setupMockClient(t, func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+		fmt.Fprint(w, `{"message":"internal server error"}`)
+	})
+	loader := gokick.NewUserLoader(kickClient, gokick.LoaderOptions{})
+
+	_, err := loader.Load(context.Background(), 1)
+	require.ErrorIs(t, err, gokick.ErrServer)
+}
+
+func TestLoaderCallerCanceled(t *testing.T) {
+	kickClient, calls := setupLoaderServer(t)
+	loader := gokick.NewUserLoader(kickClient, gokick.LoaderOptions{Wait: 50 * time.Millisecond})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := loader.Load(ctx, 1)
+	require.ErrorIs(t, err, context.Canceled)
+
+	user, err := loader.Load(context.Background(), 2)
+	require.NoError(t, err)
+	assert.Equal(t, 2, user.UserID)
+
+	require.Len(t, calls(), 1)
+	assert.Equal(t, []int{1, 2}, calls()[0], "the batch is still sent when its first caller is canceled")
+}
+
+func TestLoaderTokenSelection(t *testing.T) {
+	var mu sync.Mutex
+	calls := make(map[string][]string)
+	kickClient := setupMockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		calls[r.Header.Get("Authorization")] = append(calls[r.Header.Get("Authorization")], r.URL.Query()["id"]...)
+		mu.Unlock()
+
+		fmt.Fprint(w, `{"message":"success", "data":[]}`)
+	})
+	loader := gokick.NewUserLoader(kickClient, gokick.LoaderOptions{Wait: 20 * time.Millisecond})
+
+	var wg sync.WaitGroup
+	for i, ctx := range []context.Context{
+		context.Background(),
+		gokick.WithAccessToken(context.Background(), "broadcaster-a"),
+		gokick.WithAccessToken(context.Background(), "broadcaster-b"),
+		gokick.WithAccessToken(context.Background(), "broadcaster-a"),
+	} {
+		wg.Go(func() {
+			_, err := loader.Load(ctx, i+1)
+			assert.ErrorIs(t, err, gokick.ErrNotFound)
+		})
+	}
+	wg.Wait()
+
+	assert.Equal(t, []string{"1"}, calls["Bearer access-token"])
+	assert.ElementsMatch(t, []string{"2", "4"}, calls["Bearer broadcaster-a"])
+	assert.Equal(t, []string{"3"}, calls["Bearer broadcaster-b"])
+}
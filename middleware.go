@@ -0,0 +1,151 @@
+package gokick
+
+import (
+	"bytes"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httputil"
+	"regexp"
+	"sync"
+	"time"
+)
+
+// Middleware wraps the request pipeline of a Client. next sends the request, including retries and token refreshes;
+// a middleware may change the request before calling it, inspect the response afterwards, or not call it at all.
+type Middleware func(next http.RoundTripper) http.RoundTripper
+
+// RoundTripperFunc adapts a function to http.RoundTripper.
+type RoundTripperFunc func(req *http.Request) (*http.Response, error)
+
+func (f RoundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// pipeline returns do wrapped in the response cache, if any, then in middlewares, the first one being the outermost.
+func (c *Client) pipeline(middlewares []Middleware) http.RoundTripper {
+	var transport http.RoundTripper = RoundTripperFunc(c.do)
+	if c.options.Cache != nil {
+		next := transport
+		transport = RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			return c.options.Cache.roundTrip(c, next, req)
+		})
+	}
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		transport = middlewares[i](transport)
+	}
+
+	return transport
+}
+
+// LoggingMiddleware logs each call with its method, path, status code, request ID and duration. Failed calls are
+// logged as warnings (status code 400 and above) or errors (no response).
+func LoggingMiddleware(logger *slog.Logger) Middleware {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			start := time.Now()
+			response, err := next.RoundTrip(req)
+
+			attrs := []slog.Attr{
+				slog.String("method", req.Method),
+				slog.String("path", req.URL.Path),
+				slog.Duration("duration", time.Since(start)),
+			}
+
+			if err != nil {
+				attrs = append(attrs, slog.Any("error", err))
+				logger.LogAttrs(req.Context(), slog.LevelError, "kick request failed", attrs...)
+				return response, err
+			}
+
+			attrs = append(attrs, slog.Int("status", response.StatusCode))
+			if requestID := response.Header.Get("X-Request-Id"); requestID != "" {
+				attrs = append(attrs, slog.String("request_id", requestID))
+			}
+
+			level := slog.LevelInfo
+			if response.StatusCode >= http.StatusBadRequest {
+				level = slog.LevelWarn
+			}
+			logger.LogAttrs(req.Context(), level, "kick request", attrs...)
+
+			return response, nil
+		})
+	}
+}
+
+var redactions = []struct {
+	pattern     *regexp.Regexp
+	replacement string
+}{
+	{
+		pattern:     regexp.MustCompile(`(?im)^(Authorization:\s*\w+\s+).+$`),
+		replacement: "${1}[REDACTED]",
+	},
+	{
+		pattern:     regexp.MustCompile(`\b(client_secret|access_token|refresh_token|token|code|code_verifier)=[^&\s]+`),
+		replacement: "${1}=[REDACTED]",
+	},
+	{
+		pattern:     regexp.MustCompile(`"(client_secret|access_token|refresh_token)"(\s*:\s*)"[^"]*"`),
+		replacement: `"${1}"${2}"[REDACTED]"`,
+	},
+}
+
+// Redact masks bearer tokens, client secrets, OAuth codes and tokens in an HTTP dump.
+func Redact(dump []byte) []byte {
+	for _, redaction := range redactions {
+		dump = redaction.pattern.ReplaceAll(dump, []byte(redaction.replacement))
+	}
+
+	return dump
+}
+
+// DumpMiddleware writes each request and its response to w, including bodies, with credentials redacted.
+// It is meant for debugging: bodies are held in memory and dumps may still contain personal data.
+func DumpMiddleware(w io.Writer) Middleware {
+	var mu sync.Mutex
+
+	return func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			var body []byte
+			if req.Body != nil {
+				var err error
+				body, err = io.ReadAll(req.Body)
+				if err != nil {
+					return nil, err
+				}
+				req.Body.Close()
+				req.Body = io.NopCloser(bytes.NewReader(body))
+			}
+
+			response, err := next.RoundTrip(req)
+
+			// The request is dumped once sent, so that it includes the headers set by the client.
+			if req.Body != nil {
+				req.Body = io.NopCloser(bytes.NewReader(body))
+			}
+
+			var dump bytes.Buffer
+			requestDump, dumpErr := httputil.DumpRequest(req, true)
+			if dumpErr == nil {
+				dump.Write(requestDump)
+				dump.WriteString("\n\n")
+			}
+
+			if response != nil {
+				responseDump, dumpErr := httputil.DumpResponse(response, true)
+				if dumpErr == nil {
+					dump.Write(responseDump)
+					dump.WriteString("\n\n")
+				}
+			}
+
+			mu.Lock()
+			_, _ = w.Write(Redact(dump.Bytes()))
+			mu.Unlock()
+
+			return response, err
+		})
+	}
+}
@@ -0,0 +1,151 @@
+package gokick_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"testing"
+
+	"github.com/scorfly/gokick"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func middlewareClientOptions(middlewares ...gokick.Middleware) gokick.ClientOptions {
+	return gokick.ClientOptions{
+		UserAccessToken: "secret-access-token",
+		ClientID:        "client-id",
+		ClientSecret:    "secret-client-secret",
+		Middlewares:     middlewares,
+	}
+}
+
+func TestMiddlewares(t *testing.T) {
+	t.Run("order and header injection", func(t *testing.T) {
+		var calls []string
+		trace := func(name string) gokick.Middleware {
+			return func(next http.RoundTripper) http.RoundTripper {
+				return gokick.RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+					calls = append(calls, name+" before")
+					req.Header.Add("X-Trace", name)
+					response, err := next.RoundTrip(req)
+					calls = append(calls, name+" after")
+					return response, err
+				})
+			}
+		}
+
+		var traceHeader []string
+		options := middlewareClientOptions(trace("outer"), trace("inner"))
+		kickClient := setupMockClientWithOptions(t, options, func(w http.ResponseWriter, r *http.Request) {
+			traceHeader = r.Header.Values("X-Trace")
+			fmt.Fprint(w, `{"data":[]}`)
+		})
+
+		_, err := kickClient.GetUsers(context.Background(), gokick.NewUserListFilter())
+		require.NoError(t, err)
+		assert.Equal(t, []string{"outer before", "inner before", "inner after", "outer after"}, calls)
+		assert.Equal(t, []string{"outer", "inner"}, traceHeader)
+	})
+
+	t.Run("short circuit", func(t *testing.T) {
+		chaos := func(http.RoundTripper) http.RoundTripper {
+			return gokick.RoundTripperFunc(func(*http.Request) (*http.Response, error) {
+				return nil, errors.New("chaos")
+			})
+		}
+
+		kickClient := setupMockClientWithOptions(t, middlewareClientOptions(chaos), func(http.ResponseWriter, *http.Request) {
+			t.Error("request must not be sent")
+		})
+
+		_, err := kickClient.GetUsers(context.Background(), gokick.NewUserListFilter())
+		require.EqualError(t, err, "failed to make request: chaos")
+	})
+}
+
+func TestLoggingMiddleware(t *testing.T) {
+	var logs bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&logs, &slog.HandlerOptions{ReplaceAttr: func(_ []string, attr slog.Attr) slog.Attr {
+		if attr.Key == slog.TimeKey || attr.Key == "duration" {
+			return slog.Attr{}
+		}
+		return attr
+	}}))
+
+	options := middlewareClientOptions(gokick.LoggingMiddleware(logger))
+	kickClient := setupMockClientWithOptions(t, options, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Request-Id", "request-id")
+		if r.URL.Query().Get("id") == "404" {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"message":"not found"}`)
+			return
+		}
+		fmt.Fprint(w, `{"data":[]}`)
+	})
+
+	_, err := kickClient.GetUsers(context.Background(), gokick.NewUserListFilter())
+	require.NoError(t, err)
+	_, err = kickClient.GetUsers(context.Background(), gokick.NewUserListFilter().SetID(404))
+	require.Error(t, err)
+
+	assert.Equal(
+		t,
+		"level=INFO msg=\"kick request\" method=GET path=/public/v1/users status=200 request_id=request-id\n"+
+			"level=WARN msg=\"kick request\" method=GET path=/public/v1/users status=404 request_id=request-id\n",
+		logs.String(),
+	)
+
+	logs.Reset()
+	failingClient, err := gokick.NewClient(&gokick.ClientOptions{
+		APIBaseURL:  "http://127.0.0.1:0",
+		Middlewares: []gokick.Middleware{gokick.LoggingMiddleware(logger)},
+	})
+	require.NoError(t, err)
+
+	_, err = failingClient.GetUsers(context.Background(), gokick.NewUserListFilter())
+	require.Error(t, err)
+	assert.Contains(t, logs.String(), "level=ERROR msg=\"kick request failed\" method=GET path=/public/v1/users error=")
+}
+
+func TestDumpMiddleware(t *testing.T) {
+	var dump bytes.Buffer
+	options := middlewareClientOptions(gokick.DumpMiddleware(&dump))
+	kickClient := setupMockClientWithOptions(t, options, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/oauth/token" {
+			fmt.Fprint(w, `{"access_token":"secret-new-access","refresh_token":"secret-new-refresh","expires_in":7200}`)
+			return
+		}
+		fmt.Fprint(w, `{"data":{"message_id":"id","is_sent":true}}`)
+	})
+
+	_, err := kickClient.SendChatMessage(context.Background(), nil, "hello", nil, gokick.MessageTypeBot)
+	require.NoError(t, err)
+	_, err = kickClient.RefreshToken(context.Background(), "secret-refresh-token")
+	require.NoError(t, err)
+
+	output := dump.String()
+	assert.Contains(t, output, "POST /public/v1/chat HTTP/1.1")
+	assert.Contains(t, output, "Authorization: Bearer [REDACTED]")
+	assert.Contains(t, output, `"content":"hello"`)
+	assert.Contains(t, output, `"is_sent":true`)
+	assert.Contains(t, output, "client_secret=[REDACTED]")
+	assert.Contains(t, output, "refresh_token=[REDACTED]")
+	assert.Contains(t, output, `"access_token":"[REDACTED]"`)
+	assert.NotContains(t, output, "secret-")
+}
+
+func TestRedact(t *testing.T) {
+	assert.Equal(
+		t,
+		"Authorization: Bearer [REDACTED]\ncode=[REDACTED]&code_verifier=[REDACTED]&grant_type=authorization_code\n"+
+			`{"refresh_token": "[REDACTED]","scope":"user:read"}`,
+		string(gokick.Redact([]byte(
+			"Authorization: Bearer abc.def\ncode=123&code_verifier=456&grant_type=authorization_code\n"+
+				`{"refresh_token": "xyz","scope":"user:read"}`,
+		))),
+	)
+}
@@ -41,7 +41,7 @@ func (c *Client) BanUser(
 
 	body, err := json.Marshal(r)
 	if err != nil {
-		return BanUserResponseWrapper{}, fmt.Errorf("failed to marshal body: %v", err)
+		return BanUserResponseWrapper{}, fmt.Errorf("failed to marshal body: %w", err)
 	}
 
 	response, err := makeRequest[BanUserResponse](
@@ -76,7 +76,7 @@ func (c *Client) UnbanUser(
 
 	body, err := json.Marshal(r)
 	if err != nil {
-		return BanUserResponseWrapper{}, fmt.Errorf("failed to marshal body: %v", err)
+		return BanUserResponseWrapper{}, fmt.Errorf("failed to marshal body: %w", err)
 	}
 
 	response, err := makeRequest[BanUserResponse](
@@ -0,0 +1,257 @@
+package gokick
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+const defaultOAuthStateMaxAge = 10 * time.Minute
+
+// ErrInvalidOAuthState is returned when the state received on the OAuth callback was not issued by the OAuthFlow,
+// was tampered with, or is older than OAuthFlowOptions.StateMaxAge.
+var ErrInvalidOAuthState = errors.New("gokick: invalid OAuth state")
+
+// OAuthFlowOptions configures an OAuthFlow.
+type OAuthFlowOptions struct {
+	RedirectURI string
+	Scopes      []Scope
+	// StateSecret is the key protecting the state. The state of a flow can be validated by any OAuthFlow sharing
+	// the same secret. When empty, a random key is generated and states are only valid within this OAuthFlow.
+	StateSecret []byte
+	// StateMaxAge is how long the user has to complete the authorization. Defaults to 10 minutes.
+	StateMaxAge time.Duration
+}
+
+// OAuthFlow runs the OAuth 2.1 authorization code flow with PKCE (RFC 7636).
+//
+// The code verifier is carried in the state, encrypted and authenticated with AES-GCM, so no server-side storage
+// is needed between AuthorizeURL and Exchange.
+//
+// A state is valid for any callback until it expires. To prevent login CSRF, where an attacker sends a victim a
+// callback URL completing the attacker's own authorization, pass a binding tied to the browser session, such as
+// a random value stored in a cookie: the state is then only accepted with the same binding.
+type OAuthFlow struct {
+	client  *Client
+	options OAuthFlowOptions
+	aead    cipher.AEAD
+	now     func() time.Time
+}
+
+type oauthState struct {
+	CodeVerifier string `json:"v"`
+	IssuedAt     int64  `json:"t"`
+}
+
+func NewOAuthFlow(client *Client, options OAuthFlowOptions) (*OAuthFlow, error) {
+	if options.RedirectURI == "" {
+		return nil, fmt.Errorf("redirect URI must be set on OAuthFlow")
+	}
+
+	secret := options.StateSecret
+	if len(secret) == 0 {
+		secret = make([]byte, 32)
+		_, err := rand.Read(secret)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate state secret: %w", err)
+		}
+	}
+
+	if options.StateMaxAge == 0 {
+		options.StateMaxAge = defaultOAuthStateMaxAge
+	}
+
+	key := sha256.Sum256(secret)
+	block, err := aes.NewCipher(key[:])
+	if err != nil {
+		return nil, fmt.Errorf("failed to create state cipher: %w", err)
+	}
+
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create state cipher: %w", err)
+	}
+
+	return &OAuthFlow{
+		client:  client,
+		options: options,
+		aead:    aead,
+		now:     time.Now,
+	}, nil
+}
+
+// NewCodeVerifier returns a random PKCE code verifier of 43 characters.
+func NewCodeVerifier() (string, error) {
+	buffer := make([]byte, 32)
+	_, err := rand.Read(buffer)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate code verifier: %w", err)
+	}
+
+	return base64.RawURLEncoding.EncodeToString(buffer), nil
+}
+
+// CodeChallengeS256 returns the S256 code challenge of verifier.
+func CodeChallengeS256(verifier string) string {
+	hash := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(hash[:])
+}
+
+// AuthorizeURL starts a new flow and returns the Kick URL the user must be sent to. The state is bound to binding,
+// which must be given again to Exchange. An empty binding does not tie the state to the browser session.
+func (f *OAuthFlow) AuthorizeURL(binding string) (string, error) {
+	verifier, err := NewCodeVerifier()
+	if err != nil {
+		return "", err
+	}
+
+	state, err := f.sealState(binding, oauthState{CodeVerifier: verifier, IssuedAt: f.now().Unix()})
+	if err != nil {
+		return "", err
+	}
+
+	return f.client.GetAuthorize(f.options.RedirectURI, state, CodeChallengeS256(verifier), f.options.Scopes)
+}
+
+// Exchange validates state against binding and exchanges code for the user token pair.
+func (f *OAuthFlow) Exchange(ctx context.Context, binding, state, code string) (TokenResponse, error) {
+	payload, err := f.openState(binding, state)
+	if err != nil {
+		return TokenResponse{}, err
+	}
+
+	return f.client.GetToken(ctx, f.options.RedirectURI, code, payload.CodeVerifier)
+}
+
+// HandleCallback completes the flow from the query parameters Kick redirected the user with, binding being the
+// one given to AuthorizeURL.
+func (f *OAuthFlow) HandleCallback(ctx context.Context, binding string, query url.Values) (TokenResponse, error) {
+	if query.Get("error") != "" {
+		return TokenResponse{}, fmt.Errorf("authorization denied: %s %s", query.Get("error"), query.Get("error_description"))
+	}
+
+	code := query.Get("code")
+	if code == "" {
+		return TokenResponse{}, fmt.Errorf("missing authorization code")
+	}
+
+	return f.Exchange(ctx, binding, query.Get("state"), code)
+}
+
+// AuthorizeWithLocalServer runs the whole flow for CLI tools. It listens on the host and port of the redirect URI,
+// which must point to the local machine, passes the authorization URL to openURL (to print it or open a browser),
+// then serves a single callback and returns the resulting token. The state is bound to this call, so only the
+// callback of the URL given to openURL is accepted.
+func (f *OAuthFlow) AuthorizeWithLocalServer(ctx context.Context, openURL func(authorizeURL string)) (TokenResponse, error) {
+	redirectURI, err := url.Parse(f.options.RedirectURI)
+	if err != nil {
+		return TokenResponse{}, fmt.Errorf("failed to parse redirect URI: %w", err)
+	}
+
+	callbackPath := redirectURI.Path
+	if callbackPath == "" {
+		callbackPath = "/"
+	}
+
+	binding := rand.Text()
+	authorizeURL, err := f.AuthorizeURL(binding)
+	if err != nil {
+		return TokenResponse{}, err
+	}
+
+	listener, err := (&net.ListenConfig{}).Listen(ctx, "tcp", redirectURI.Host)
+	if err != nil {
+		return TokenResponse{}, fmt.Errorf("failed to listen for OAuth callback: %w", err)
+	}
+	defer listener.Close()
+
+	type result struct {
+		token TokenResponse
+		err   error
+	}
+	results := make(chan result, 1)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(callbackPath, func(w http.ResponseWriter, r *http.Request) {
+		token, err := f.HandleCallback(r.Context(), binding, r.URL.Query())
+		if errors.Is(err, ErrInvalidOAuthState) {
+			http.Error(w, "Invalid state", http.StatusBadRequest)
+			return
+		}
+
+		if err != nil {
+			http.Error(w, "Authorization failed, you can close this window.", http.StatusBadRequest)
+		} else {
+			fmt.Fprint(w, "Authorization complete, you can close this window.")
+		}
+		_ = http.NewResponseController(w).Flush()
+
+		select {
+		case results <- result{token: token, err: err}:
+		default:
+		}
+	})
+
+	server := &http.Server{Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+	go func() { _ = server.Serve(listener) }()
+	defer server.Close()
+
+	openURL(authorizeURL)
+
+	select {
+	case r := <-results:
+		return r.token, r.err
+	case <-ctx.Done():
+		return TokenResponse{}, ctx.Err()
+	}
+}
+
+func (f *OAuthFlow) sealState(binding string, state oauthState) (string, error) {
+	plaintext, err := json.Marshal(state)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal state: %w", err)
+	}
+
+	nonce := make([]byte, f.aead.NonceSize())
+	_, err = rand.Read(nonce)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate state nonce: %w", err)
+	}
+
+	return base64.RawURLEncoding.EncodeToString(f.aead.Seal(nonce, nonce, plaintext, []byte(binding))), nil
+}
+
+func (f *OAuthFlow) openState(binding, value string) (oauthState, error) {
+	sealed, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil || len(sealed) < f.aead.NonceSize() {
+		return oauthState{}, ErrInvalidOAuthState
+	}
+
+	nonce, ciphertext := sealed[:f.aead.NonceSize()], sealed[f.aead.NonceSize():]
+	plaintext, err := f.aead.Open(nil, nonce, ciphertext, []byte(binding))
+	if err != nil {
+		return oauthState{}, ErrInvalidOAuthState
+	}
+
+	var state oauthState
+	err = json.Unmarshal(plaintext, &state)
+	if err != nil {
+		return oauthState{}, ErrInvalidOAuthState
+	}
+
+	if f.now().Sub(time.Unix(state.IssuedAt, 0)) > f.options.StateMaxAge {
+		return oauthState{}, fmt.Errorf("%w: state expired", ErrInvalidOAuthState)
+	}
+
+	return state, nil
+}
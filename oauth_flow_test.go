@@ -0,0 +1,218 @@
+package gokick_test
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/scorfly/gokick"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupOAuthFlowClient mocks the token endpoint, which only accepts code "code" with the verifier matching challenge.
+func setupOAuthFlowClient(t *testing.T, challenge *string) *gokick.Client {
+	t.Helper()
+
+	return setupMockAuthClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.FormValue("code") != "code" || gokick.CodeChallengeS256(r.FormValue("code_verifier")) != *challenge {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(w, `{"error":"invalid_grant"}`)
+			return
+		}
+
+		fmt.Fprint(w, `{"access_token":"access","refresh_token":"refresh","expires_in":7200,"token_type":"Bearer"}`)
+	})
+}
+
+func authorizeURLQuery(t *testing.T, authorizeURL string) url.Values {
+	t.Helper()
+
+	parsed, err := url.Parse(authorizeURL)
+	require.NoError(t, err)
+
+	return parsed.Query()
+}
+
+func TestCodeChallengeS256(t *testing.T) {
+	assert.Equal(t, "MbuxMsd3PomLjN8QePu_pP01g2ZZFntID1NlyEXYoyc", gokick.CodeChallengeS256("dBjftJeZ4CVP-mJ92K9qnyg-5oF2UNbHkDYQWCsi3KY"))
+
+	verifier, err := gokick.NewCodeVerifier()
+	require.NoError(t, err)
+	assert.Len(t, verifier, 43)
+}
+
+func TestOAuthFlow(t *testing.T) {
+	t.Run("authorize and exchange", func(t *testing.T) {
+		var challenge string
+		flow, err := gokick.NewOAuthFlow(setupOAuthFlowClient(t, &challenge), gokick.OAuthFlowOptions{
+			RedirectURI: "http://localhost:3000/callback",
+			Scopes:      []gokick.Scope{gokick.ScopeUserRead},
+		})
+		require.NoError(t, err)
+
+		authorizeURL, err := flow.AuthorizeURL("session")
+		require.NoError(t, err)
+
+		query := authorizeURLQuery(t, authorizeURL)
+		assert.Equal(t, "http://localhost:3000/callback", query.Get("redirect_uri"))
+		assert.Equal(t, "S256", query.Get("code_challenge_method"))
+		assert.Equal(t, "user:read", query.Get("scope"))
+		challenge = query.Get("code_challenge")
+
+		token, err := flow.HandleCallback(context.Background(), "session", url.Values{"code": {"code"}, "state": {query.Get("state")}})
+		require.NoError(t, err)
+		assert.Equal(t, "access", token.AccessToken)
+	})
+
+	t.Run("state is bound to the session", func(t *testing.T) {
+		var challenge string
+		flow, err := gokick.NewOAuthFlow(setupOAuthFlowClient(t, &challenge), gokick.OAuthFlowOptions{
+			RedirectURI: "http://localhost:3000/callback",
+		})
+		require.NoError(t, err)
+
+		authorizeURL, err := flow.AuthorizeURL("attacker-session")
+		require.NoError(t, err)
+		query := authorizeURLQuery(t, authorizeURL)
+		challenge = query.Get("code_challenge")
+
+		for _, binding := range []string{"victim-session", ""} {
+			_, err = flow.Exchange(context.Background(), binding, query.Get("state"), "code")
+			require.ErrorIs(t, err, gokick.ErrInvalidOAuthState)
+		}
+
+		_, err = flow.Exchange(context.Background(), "attacker-session", query.Get("state"), "code")
+		require.NoError(t, err)
+	})
+
+	t.Run("state is shared between flows with the same secret", func(t *testing.T) {
+		var challenge string
+		kickClient := setupOAuthFlowClient(t, &challenge)
+		options := gokick.OAuthFlowOptions{RedirectURI: "http://localhost:3000/callback", StateSecret: []byte("secret")}
+
+		flow, err := gokick.NewOAuthFlow(kickClient, options)
+		require.NoError(t, err)
+		authorizeURL, err := flow.AuthorizeURL("")
+		require.NoError(t, err)
+		query := authorizeURLQuery(t, authorizeURL)
+		challenge = query.Get("code_challenge")
+
+		otherFlow, err := gokick.NewOAuthFlow(kickClient, options)
+		require.NoError(t, err)
+		_, err = otherFlow.Exchange(context.Background(), "", query.Get("state"), "code")
+		require.NoError(t, err)
+
+		options.StateSecret = []byte("other-secret")
+		otherFlow, err = gokick.NewOAuthFlow(kickClient, options)
+		require.NoError(t, err)
+		_, err = otherFlow.Exchange(context.Background(), "", query.Get("state"), "code")
+		require.ErrorIs(t, err, gokick.ErrInvalidOAuthState)
+	})
+
+	t.Run("invalid state", func(t *testing.T) {
+		flow, err := gokick.NewOAuthFlow(setupOAuthFlowClient(t, new(string)), gokick.OAuthFlowOptions{RedirectURI: "http://localhost/callback"})
+		require.NoError(t, err)
+
+		for _, state := range []string{"", "not base64!", "c2hvcnQ", "dGhpcyBpcyBub3QgYSBzZWFsZWQgc3RhdGUgdmFsdWU"} {
+			_, err = flow.Exchange(context.Background(), "", state, "code")
+			require.ErrorIs(t, err, gokick.ErrInvalidOAuthState)
+		}
+	})
+
+	t.Run("expired state", func(t *testing.T) {
+		now := time.Now()
+		flow, err := gokick.NewOAuthFlow(setupOAuthFlowClient(t, new(string)), gokick.OAuthFlowOptions{
+			RedirectURI: "http://localhost/callback",
+			StateMaxAge: time.Minute,
+		})
+		require.NoError(t, err)
+		gokick.SetOAuthFlowNow(flow, func() time.Time { return now })
+
+		authorizeURL, err := flow.AuthorizeURL("")
+		require.NoError(t, err)
+		now = now.Add(2 * time.Minute)
+
+		_, err = flow.Exchange(context.Background(), "", authorizeURLQuery(t, authorizeURL).Get("state"), "code")
+		require.ErrorIs(t, err, gokick.ErrInvalidOAuthState)
+		require.EqualError(t, err, "gokick: invalid OAuth state: state expired")
+	})
+
+	t.Run("callback errors", func(t *testing.T) {
+		flow, err := gokick.NewOAuthFlow(setupOAuthFlowClient(t, new(string)), gokick.OAuthFlowOptions{RedirectURI: "http://localhost/callback"})
+		require.NoError(t, err)
+
+		_, err = flow.HandleCallback(context.Background(), "", url.Values{"error": {"access_denied"}, "error_description": {"user refused"}})
+		require.EqualError(t, err, "authorization denied: access_denied user refused")
+
+		_, err = flow.HandleCallback(context.Background(), "", url.Values{"state": {"state"}})
+		require.EqualError(t, err, "missing authorization code")
+	})
+
+	t.Run("missing redirect URI", func(t *testing.T) {
+		_, err := gokick.NewOAuthFlow(setupOAuthFlowClient(t, new(string)), gokick.OAuthFlowOptions{})
+		require.EqualError(t, err, "redirect URI must be set on OAuthFlow")
+	})
+}
+
+func TestOAuthFlowLocalServer(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	redirectURI := fmt.Sprintf("http://%s/oauth/callback", listener.Addr())
+	require.NoError(t, listener.Close())
+
+	var challenge string
+	flow, err := gokick.NewOAuthFlow(setupOAuthFlowClient(t, &challenge), gokick.OAuthFlowOptions{RedirectURI: redirectURI})
+	require.NoError(t, err)
+
+	callbackStatus := make(chan int, 2)
+	token, err := flow.AuthorizeWithLocalServer(context.Background(), func(authorizeURL string) {
+		query := authorizeURLQuery(t, authorizeURL)
+		challenge = query.Get("code_challenge")
+
+		go func() {
+			for _, state := range []string{"forged", query.Get("state")} {
+				response, err := http.Get(redirectURI + "?" + url.Values{"code": {"code"}, "state": {state}}.Encode())
+				if !assert.NoError(t, err) {
+					return
+				}
+				response.Body.Close()
+				callbackStatus <- response.StatusCode
+			}
+		}()
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "access", token.AccessToken)
+	assert.Equal(t, http.StatusBadRequest, <-callbackStatus)
+	assert.Equal(t, http.StatusOK, <-callbackStatus)
+}
+
+func TestOAuthFlowLocalServerWithoutPath(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	address := listener.Addr().String()
+	require.NoError(t, listener.Close())
+
+	flow, err := gokick.NewOAuthFlow(setupOAuthFlowClient(t, new(string)), gokick.OAuthFlowOptions{RedirectURI: "http://" + address})
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	_, err = flow.AuthorizeWithLocalServer(ctx, func(string) {
+		defer cancel()
+
+		response, err := http.Get("http://" + address + "?" + url.Values{"code": {"code"}, "state": {"forged"}}.Encode())
+		if assert.NoError(t, err) {
+			response.Body.Close()
+			assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+		}
+	})
+	require.ErrorIs(t, err, context.Canceled)
+
+	listener, err = net.Listen("tcp", address)
+	require.NoError(t, err, "the callback listener is closed")
+	require.NoError(t, listener.Close())
+}
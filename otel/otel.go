@@ -0,0 +1,224 @@
+// Package gokickotel instruments gokick clients and webhook handlers with OpenTelemetry traces and metrics.
+//
+// It lives in its own module so that applications not using OpenTelemetry do not depend on it.
+package gokickotel
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/scorfly/gokick"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "github.com/scorfly/gokick/otel"
+
+type config struct {
+	tracerProvider trace.TracerProvider
+	meterProvider  metric.MeterProvider
+}
+
+// Option configures an Instrumentation.
+type Option func(*config)
+
+// WithTracerProvider sets the tracer provider. Defaults to the global one.
+func WithTracerProvider(provider trace.TracerProvider) Option {
+	return func(c *config) {
+		c.tracerProvider = provider
+	}
+}
+
+// WithMeterProvider sets the meter provider. Defaults to the global one.
+func WithMeterProvider(provider metric.MeterProvider) Option {
+	return func(c *config) {
+		c.meterProvider = provider
+	}
+}
+
+// Instrumentation creates spans and records metrics for gokick API calls and webhook events.
+type Instrumentation struct {
+	tracer          trace.Tracer
+	requestDuration metric.Float64Histogram
+	requestErrors   metric.Int64Counter
+	retries         metric.Int64Counter
+	tokenRefreshes  metric.Int64Counter
+	webhookEvents   metric.Int64Counter
+	webhookDuration metric.Float64Histogram
+}
+
+func New(options ...Option) (*Instrumentation, error) {
+	cfg := config{
+		tracerProvider: otel.GetTracerProvider(),
+		meterProvider:  otel.GetMeterProvider(),
+	}
+	for _, option := range options {
+		option(&cfg)
+	}
+
+	meter := cfg.meterProvider.Meter(instrumentationName)
+	i := &Instrumentation{tracer: cfg.tracerProvider.Tracer(instrumentationName)}
+
+	var err error
+	i.requestDuration, err = meter.Float64Histogram(
+		"gokick.client.request.duration",
+		metric.WithUnit("s"),
+		metric.WithDescription("Duration of Kick API calls, retries included."),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request duration histogram: %w", err)
+	}
+
+	i.requestErrors, err = meter.Int64Counter(
+		"gokick.client.request.errors",
+		metric.WithDescription("Kick API calls that failed, with a status code of 400 or above or without response."),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request errors counter: %w", err)
+	}
+
+	i.retries, err = meter.Int64Counter("gokick.client.retries", metric.WithDescription("Retried attempts of Kick API calls."))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create retries counter: %w", err)
+	}
+
+	i.tokenRefreshes, err = meter.Int64Counter("gokick.client.token_refreshes", metric.WithDescription("Token refreshes sent to Kick."))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create token refreshes counter: %w", err)
+	}
+
+	i.webhookEvents, err = meter.Int64Counter("gokick.webhook.events", metric.WithDescription("Webhook events received."))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create webhook events counter: %w", err)
+	}
+
+	i.webhookDuration, err = meter.Float64Histogram(
+		"gokick.webhook.duration",
+		metric.WithUnit("s"),
+		metric.WithDescription("Duration of webhook event processing."),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create webhook duration histogram: %w", err)
+	}
+
+	return i, nil
+}
+
+// Middleware returns a gokick.Middleware creating a client span per API call, named after its method and route
+// (e.g. "POST /public/v1/chat"), and recording its duration, errors, retries and token refreshes.
+func (i *Instrumentation) Middleware() gokick.Middleware {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return gokick.RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			route := gokick.Route(req.URL.Path)
+			attrs := []attribute.KeyValue{
+				attribute.String("http.request.method", req.Method),
+				attribute.String("http.route", route),
+			}
+
+			ctx, span := i.tracer.Start(
+				req.Context(),
+				req.Method+" "+route,
+				trace.WithSpanKind(trace.SpanKindClient),
+				trace.WithAttributes(append(attrs, attribute.String("url.path", req.URL.Path))...),
+			)
+			defer span.End()
+
+			retryCount := 0
+			hooks := i.clientTrace(ctx, span, attrs, &retryCount)
+
+			start := time.Now()
+			response, err := next.RoundTrip(req.WithContext(gokick.WithTrace(ctx, hooks)))
+			duration := time.Since(start)
+
+			span.SetAttributes(attribute.Int("gokick.retry_count", retryCount))
+
+			switch {
+			case err != nil:
+				attrs = append(attrs, attribute.String("error.type", errorType(err)))
+				span.RecordError(err)
+				span.SetStatus(codes.Error, err.Error())
+			case response.StatusCode >= http.StatusBadRequest:
+				attrs = append(attrs,
+					attribute.Int("http.response.status_code", response.StatusCode),
+					attribute.String("error.type", fmt.Sprintf("%d", response.StatusCode)),
+				)
+				span.SetAttributes(attribute.Int("http.response.status_code", response.StatusCode))
+				span.SetStatus(codes.Error, http.StatusText(response.StatusCode))
+			default:
+				attrs = append(attrs, attribute.Int("http.response.status_code", response.StatusCode))
+				span.SetAttributes(attribute.Int("http.response.status_code", response.StatusCode))
+			}
+
+			i.requestDuration.Record(ctx, duration.Seconds(), metric.WithAttributes(attrs...))
+			if err != nil || response.StatusCode >= http.StatusBadRequest {
+				i.requestErrors.Add(ctx, 1, metric.WithAttributes(attrs...))
+			}
+
+			return response, err
+		})
+	}
+}
+
+// clientTrace returns the hooks recording retries and token refreshes on span, chained to the Trace already
+// carried by ctx, if any.
+func (i *Instrumentation) clientTrace(ctx context.Context, span trace.Span, attrs []attribute.KeyValue, retryCount *int) *gokick.Trace {
+	parent := gokick.ContextTrace(ctx)
+	if parent == nil {
+		parent = &gokick.Trace{}
+	}
+
+	hooks := *parent
+	hooks.Retry = func(attempt int, delay time.Duration, response *http.Response, err error) {
+		*retryCount++
+
+		eventAttrs := []attribute.KeyValue{attribute.Int("attempt", attempt), attribute.String("delay", delay.String())}
+		if response != nil {
+			eventAttrs = append(eventAttrs, attribute.Int("http.response.status_code", response.StatusCode))
+		}
+		if err != nil {
+			eventAttrs = append(eventAttrs, attribute.String("error.type", errorType(err)))
+		}
+		span.AddEvent("retry", trace.WithAttributes(eventAttrs...))
+		i.retries.Add(ctx, 1, metric.WithAttributes(attrs...))
+
+		if parent.Retry != nil {
+			parent.Retry(attempt, delay, response, err)
+		}
+	}
+	hooks.TokenRefreshed = func(kind string, err error) {
+		outcome := "success"
+		if err != nil {
+			outcome = "failure"
+		}
+
+		span.SetAttributes(attribute.Bool("gokick.token_refreshed", true))
+		span.AddEvent("token_refresh", trace.WithAttributes(attribute.String("kind", kind), attribute.String("outcome", outcome)))
+		i.tokenRefreshes.Add(ctx, 1, metric.WithAttributes(attribute.String("kind", kind), attribute.String("outcome", outcome)))
+
+		if parent.TokenRefreshed != nil {
+			parent.TokenRefreshed(kind, err)
+		}
+	}
+
+	return &hooks
+}
+
+func errorType(err error) string {
+	var kickErr gokick.Error
+	switch {
+	case errors.As(err, &kickErr):
+		return fmt.Sprintf("%d", kickErr.Code())
+	case errors.Is(err, context.Canceled):
+		return "canceled"
+	case errors.Is(err, context.DeadlineExceeded):
+		return "timeout"
+	default:
+		return fmt.Sprintf("%T", err)
+	}
+}
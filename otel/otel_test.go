@@ -0,0 +1,243 @@
+package gokickotel_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/scorfly/gokick"
+	gokickotel "github.com/scorfly/gokick/otel"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func setupInstrumentation(t *testing.T) (*gokickotel.Instrumentation, *tracetest.SpanRecorder, *sdkmetric.ManualReader) {
+	t.Helper()
+
+	spans := tracetest.NewSpanRecorder()
+	reader := sdkmetric.NewManualReader()
+
+	instrumentation, err := gokickotel.New(
+		gokickotel.WithTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spans))),
+		gokickotel.WithMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))),
+	)
+	require.NoError(t, err)
+
+	return instrumentation, spans, reader
+}
+
+func collectMetrics(t *testing.T, reader *sdkmetric.ManualReader) map[string]metricdata.Aggregation {
+	t.Helper()
+
+	var data metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(context.Background(), &data))
+
+	metrics := make(map[string]metricdata.Aggregation)
+	for _, scope := range data.ScopeMetrics {
+		for _, m := range scope.Metrics {
+			metrics[m.Name] = m.Data
+		}
+	}
+
+	return metrics
+}
+
+func spanAttributes(span sdktrace.ReadOnlySpan) map[attribute.Key]attribute.Value {
+	attrs := make(map[attribute.Key]attribute.Value)
+	for _, attr := range span.Attributes() {
+		attrs[attr.Key] = attr.Value
+	}
+
+	return attrs
+}
+
+func TestMiddleware(t *testing.T) {
+	instrumentation, spans, reader := setupInstrumentation(t)
+
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/oauth/token":
+			fmt.Fprint(w, `{"access_token":"new-access","refresh_token":"new-refresh","expires_in":7200}`)
+		case r.Header.Get("Authorization") != "Bearer new-access":
+			w.WriteHeader(http.StatusUnauthorized)
+			fmt.Fprint(w, `{"message":"unauthorized"}`)
+		case calls.Add(1) == 1:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		default:
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	kickClient, err := gokick.NewClient(&gokick.ClientOptions{
+		UserAccessToken:  "old-access",
+		UserRefreshToken: "old-refresh",
+		ClientID:         "client-id",
+		ClientSecret:     "client-secret",
+		APIBaseURL:       server.URL,
+		AuthBaseURL:      server.URL,
+		RetryPolicy:      &gokick.RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: 10 * time.Millisecond},
+		Middlewares:      []gokick.Middleware{instrumentation.Middleware()},
+	})
+	require.NoError(t, err)
+
+	_, err = kickClient.DeleteChatMessage(context.Background(), "message-id")
+	require.NoError(t, err)
+
+	ended := spans.Ended()
+	require.Len(t, ended, 2)
+
+	refresh, call := ended[0], ended[1]
+	assert.Equal(t, "POST /oauth/token", refresh.Name())
+	assert.Equal(t, call.SpanContext().SpanID(), refresh.Parent().SpanID())
+
+	assert.Equal(t, "DELETE /public/v1/chat/{message_id}", call.Name())
+	attrs := spanAttributes(call)
+	assert.Equal(t, int64(204), attrs["http.response.status_code"].AsInt64())
+	assert.Equal(t, int64(1), attrs["gokick.retry_count"].AsInt64())
+	assert.True(t, attrs["gokick.token_refreshed"].AsBool())
+	assert.Equal(t, "/public/v1/chat/message-id", attrs["url.path"].AsString())
+
+	var events []string
+	for _, event := range call.Events() {
+		events = append(events, event.Name)
+	}
+	assert.Equal(t, []string{"token_refresh", "retry"}, events)
+
+	metrics := collectMetrics(t, reader)
+	assert.Equal(t, int64(1), metrics["gokick.client.retries"].(metricdata.Sum[int64]).DataPoints[0].Value)
+	assert.Equal(t, int64(1), metrics["gokick.client.token_refreshes"].(metricdata.Sum[int64]).DataPoints[0].Value)
+	assert.Len(t, metrics["gokick.client.request.duration"].(metricdata.Histogram[float64]).DataPoints, 2)
+	assert.NotContains(t, metrics, "gokick.client.request.errors")
+}
+
+func TestMiddlewareConcurrentTokenRefresh(t *testing.T) {
+	instrumentation, spans, reader := setupInstrumentation(t)
+
+	var refreshes atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/oauth/token":
+			refreshes.Add(1)
+			fmt.Fprint(w, `{"access_token":"new-access","refresh_token":"new-refresh","expires_in":7200}`)
+		case r.Header.Get("Authorization") != "Bearer new-access":
+			w.WriteHeader(http.StatusUnauthorized)
+			fmt.Fprint(w, `{"message":"unauthorized"}`)
+		default:
+			fmt.Fprint(w, `{"data":[]}`)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	kickClient, err := gokick.NewClient(&gokick.ClientOptions{
+		UserAccessToken:  "old-access",
+		UserRefreshToken: "old-refresh",
+		ClientID:         "client-id",
+		ClientSecret:     "client-secret",
+		APIBaseURL:       server.URL,
+		AuthBaseURL:      server.URL,
+		Middlewares:      []gokick.Middleware{instrumentation.Middleware()},
+	})
+	require.NoError(t, err)
+
+	var wg sync.WaitGroup
+	for range 20 {
+		wg.Go(func() {
+			_, err := kickClient.GetUsers(context.Background(), gokick.NewUserListFilter())
+			assert.NoError(t, err)
+		})
+	}
+	wg.Wait()
+
+	refreshed := 0
+	for _, span := range spans.Ended() {
+		if spanAttributes(span)["gokick.token_refreshed"].AsBool() {
+			refreshed++
+		}
+	}
+	assert.Equal(t, int32(1), refreshes.Load())
+	assert.Equal(t, 1, refreshed)
+
+	metrics := collectMetrics(t, reader)
+	assert.Equal(t, int64(1), metrics["gokick.client.token_refreshes"].(metricdata.Sum[int64]).DataPoints[0].Value)
+}
+
+func TestMiddlewareError(t *testing.T) {
+	instrumentation, spans, reader := setupInstrumentation(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(w, `{"message":"not found"}`)
+	}))
+	t.Cleanup(server.Close)
+
+	kickClient, err := gokick.NewClient(&gokick.ClientOptions{
+		APIBaseURL:  server.URL,
+		Middlewares: []gokick.Middleware{instrumentation.Middleware()},
+	})
+	require.NoError(t, err)
+
+	_, err = kickClient.GetUsers(context.Background(), gokick.NewUserListFilter())
+	require.ErrorIs(t, err, gokick.ErrNotFound)
+
+	ended := spans.Ended()
+	require.Len(t, ended, 1)
+	assert.Equal(t, codes.Error, ended[0].Status().Code)
+
+	errors := collectMetrics(t, reader)["gokick.client.request.errors"].(metricdata.Sum[int64])
+	require.Len(t, errors.DataPoints, 1)
+	errorType, _ := errors.DataPoints[0].Attributes.Value("error.type")
+	assert.Equal(t, "404", errorType.AsString())
+}
+
+func TestWebhookHandler(t *testing.T) {
+	gokick.SkipSignatureValidation = true
+	t.Cleanup(func() { gokick.SkipSignatureValidation = false })
+
+	instrumentation, spans, reader := setupInstrumentation(t)
+
+	var callbackSpan trace.SpanContext
+	handler := gokick.NewEventHandler()
+	handler.OnChannelFollow(func(ctx context.Context, _ *gokick.ChannelFollowEvent, _ gokick.EventMeta) error {
+		callbackSpan = trace.SpanContextFromContext(ctx)
+		return nil
+	})
+
+	for _, body := range []string{`{`, `{"follower":{"user_id":1}}`} {
+		req := httptest.NewRequest(http.MethodPost, "/webhook", strings.NewReader(body))
+		req.Header.Set("Kick-Event-Type", "channel.followed")
+		req.Header.Set("Kick-Event-Version", "1")
+		req.Header.Set("Kick-Event-Message-Id", "message-id")
+
+		instrumentation.WebhookHandler(handler).ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	var names []string
+	for _, span := range spans.Ended() {
+		names = append(names, span.Name())
+	}
+	assert.Equal(t, []string{
+		"verify webhook signature", "parse webhook event", "kick webhook channel.followed",
+		"verify webhook signature", "parse webhook event", "dispatch webhook event", "kick webhook channel.followed",
+	}, names)
+	assert.Equal(t, codes.Error, spans.Ended()[2].Status().Code)
+	assert.Equal(t, codes.Unset, spans.Ended()[6].Status().Code)
+	assert.Equal(t, spans.Ended()[5].SpanContext(), callbackSpan, "callbacks run within the dispatch span")
+
+	events := collectMetrics(t, reader)["gokick.webhook.events"].(metricdata.Sum[int64])
+	assert.Len(t, events.DataPoints, 2)
+}
@@ -0,0 +1,135 @@
+package gokickotel
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/scorfly/gokick"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *statusRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
+
+// WebhookHandler wraps the HTTP handler receiving Kick webhooks, either a gokick.EventHandler or a handler calling
+// gokick.GetEventFromRequest. It creates a server span per event, with child spans for the signature verification,
+// the event parsing and the EventHandler dispatch, and counts the events received. The callbacks of the EventHandler
+// run with the context of the dispatch span, so the spans of their API calls are its children.
+func (i *Instrumentation) WebhookHandler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, span := i.tracer.Start(r.Context(), "kick webhook", trace.WithSpanKind(trace.SpanKindServer))
+		defer span.End()
+
+		var subscriptionName, version string
+		hooks := i.webhookTrace(ctx, span, &subscriptionName, &version)
+
+		recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		start := time.Now()
+		next.ServeHTTP(recorder, r.WithContext(gokick.WithTrace(ctx, hooks)))
+
+		span.SetAttributes(attribute.Int("http.response.status_code", recorder.status))
+		if recorder.status >= http.StatusInternalServerError {
+			span.SetStatus(codes.Error, http.StatusText(recorder.status))
+		}
+
+		attrs := metric.WithAttributes(
+			attribute.String("gokick.subscription_name", subscriptionName),
+			attribute.String("gokick.event_version", version),
+			attribute.Int("http.response.status_code", recorder.status),
+		)
+		i.webhookEvents.Add(ctx, 1, attrs)
+		i.webhookDuration.Record(ctx, time.Since(start).Seconds(), attrs)
+	})
+}
+
+// webhookTrace returns the hooks creating the child spans of span, chained to the Trace already carried by ctx.
+func (i *Instrumentation) webhookTrace(ctx context.Context, span trace.Span, subscriptionName, version *string) *gokick.Trace {
+	parent := gokick.ContextTrace(ctx)
+	if parent == nil {
+		parent = &gokick.Trace{}
+	}
+
+	var child trace.Span
+	startChild := func(parentCtx context.Context, name string) context.Context {
+		var childCtx context.Context
+		childCtx, child = i.tracer.Start(parentCtx, name)
+		return childCtx
+	}
+	endChild := func(err error) {
+		if child == nil {
+			return
+		}
+		if err != nil {
+			child.RecordError(err)
+			child.SetStatus(codes.Error, err.Error())
+			span.SetStatus(codes.Error, err.Error())
+		}
+		child.End()
+		child = nil
+	}
+
+	hooks := *parent
+	hooks.WebhookVerifyStart = func() {
+		startChild(ctx, "verify webhook signature")
+		if parent.WebhookVerifyStart != nil {
+			parent.WebhookVerifyStart()
+		}
+	}
+	hooks.WebhookVerifyDone = func(err error) {
+		endChild(err)
+		if parent.WebhookVerifyDone != nil {
+			parent.WebhookVerifyDone(err)
+		}
+	}
+	hooks.WebhookParseStart = func(name gokick.SubscriptionName, eventVersion string) {
+		*subscriptionName = name.String()
+		*version = eventVersion
+		span.SetName("kick webhook " + name.String())
+		span.SetAttributes(attribute.String("gokick.subscription_name", name.String()), attribute.String("gokick.event_version", eventVersion))
+		startChild(ctx, "parse webhook event")
+		if parent.WebhookParseStart != nil {
+			parent.WebhookParseStart(name, eventVersion)
+		}
+	}
+	hooks.WebhookParseDone = func(err error) {
+		endChild(err)
+		if parent.WebhookParseDone != nil {
+			parent.WebhookParseDone(err)
+		}
+	}
+	hooks.WebhookDispatchStart = func(dispatchCtx context.Context, meta gokick.EventMeta) context.Context {
+		span.SetAttributes(attribute.String("gokick.message_id", meta.MessageID))
+		dispatchCtx = startChild(dispatchCtx, "dispatch webhook event")
+		if parent.WebhookDispatchStart != nil {
+			parentCtx := parent.WebhookDispatchStart(dispatchCtx, meta)
+			if parentCtx != nil {
+				dispatchCtx = parentCtx
+			}
+		}
+
+		return dispatchCtx
+	}
+	hooks.WebhookDispatchDone = func(err error) {
+		endChild(err)
+		if parent.WebhookDispatchDone != nil {
+			parent.WebhookDispatchDone(err)
+		}
+	}
+
+	return &hooks
+}
@@ -0,0 +1,87 @@
+package gokick
+
+import (
+	"context"
+	"iter"
+)
+
+// PageFetcher fetches the page starting at cursor; an empty cursor requests the first page.
+type PageFetcher[T any] func(ctx context.Context, cursor string) (PaginatedResponse[[]T], error)
+
+// Pager walks a cursor-paginated endpoint, following Pagination.NextCursor until the last page.
+type Pager[T any] struct {
+	fetch    PageFetcher[T]
+	cursor   string
+	done     bool
+	maxItems int
+	fetched  int
+}
+
+// NewPager returns a Pager starting at the first page returned by fetch.
+func NewPager[T any](fetch PageFetcher[T]) *Pager[T] {
+	return &Pager[T]{fetch: fetch}
+}
+
+// SetMaxItems stops the pager once maxItems items have been returned. Zero or less means no limit.
+func (p *Pager[T]) SetMaxItems(maxItems int) *Pager[T] {
+	p.maxItems = maxItems
+	return p
+}
+
+// HasNext reports whether Next may return more items.
+func (p *Pager[T]) HasNext() bool {
+	return !p.done
+}
+
+// Next fetches the next page. It returns nil once all pages have been read.
+func (p *Pager[T]) Next(ctx context.Context) ([]T, error) {
+	if p.done {
+		return nil, nil
+	}
+
+	err := ctx.Err()
+	if err != nil {
+		return nil, err
+	}
+
+	page, err := p.fetch(ctx, p.cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	items := page.Result
+	if p.maxItems > 0 && p.fetched+len(items) >= p.maxItems {
+		items = items[:p.maxItems-p.fetched]
+		p.done = true
+	}
+	p.fetched += len(items)
+
+	next := page.Pagination.NextCursor
+	if next == "" || next == p.cursor || len(page.Result) == 0 {
+		p.done = true
+	}
+	p.cursor = next
+
+	return items, nil
+}
+
+// All returns an iterator over every remaining item. Iteration stops at the first error,
+// which is yielded with the zero value of T.
+func (p *Pager[T]) All(ctx context.Context) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		for p.HasNext() {
+			items, err := p.Next(ctx)
+			if err != nil {
+				var zero T
+				yield(zero, err)
+				return
+			}
+
+			for _, item := range items {
+				if !yield(item, nil) {
+					return
+				}
+			}
+		}
+	}
+}
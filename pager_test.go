@@ -0,0 +1,184 @@
+package gokick_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/scorfly/gokick"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupPaginatedCategoriesClient(t *testing.T, requestedCursors *[]string) *gokick.Client {
+	t.Helper()
+
+	pages := map[string]string{
+		"":        `{"data":[{"id":1},{"id":2}],"pagination":{"next_cursor":"page-2"}}`,
+		"page-2":  `{"data":[{"id":3},{"id":4}],"pagination":{"next_cursor":"page-3"}}`,
+		"page-3":  `{"data":[{"id":5}],"pagination":{"next_cursor":""}}`,
+		"invalid": `{"message":"invalid cursor", "data":{}}`,
+	}
+
+	return setupMockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		cursor := r.URL.Query().Get("cursor")
+		if requestedCursors != nil {
+			*requestedCursors = append(*requestedCursors, cursor)
+		}
+
+		if cursor == "invalid" {
+			w.WriteHeader(http.StatusBadRequest)
+		}
+		fmt.Fprint(w, pages[cursor])
+	})
+}
+
+func collectCategoryIDs(t *testing.T, seq func(yield func(gokick.CategoryResponse, error) bool)) []int {
+	t.Helper()
+
+	var ids []int
+	for category, err := range seq {
+		require.NoError(t, err)
+		ids = append(ids, category.ID)
+	}
+
+	return ids
+}
+
+func TestAllCategories(t *testing.T) {
+	t.Run("follows cursors until the end", func(t *testing.T) {
+		var cursors []string
+		kickClient := setupPaginatedCategoriesClient(t, &cursors)
+
+		ids := collectCategoryIDs(t, kickClient.AllCategories(context.Background(), gokick.NewCategoryListFilter()))
+		assert.Equal(t, []int{1, 2, 3, 4, 5}, ids)
+		assert.Equal(t, []string{"", "page-2", "page-3"}, cursors)
+	})
+
+	t.Run("starts at the filter cursor", func(t *testing.T) {
+		kickClient := setupPaginatedCategoriesClient(t, nil)
+
+		ids := collectCategoryIDs(t, kickClient.AllCategories(context.Background(), gokick.NewCategoryListFilter().SetCursor("page-2")))
+		assert.Equal(t, []int{3, 4, 5}, ids)
+	})
+
+	t.Run("max items", func(t *testing.T) {
+		var cursors []string
+		kickClient := setupPaginatedCategoriesClient(t, &cursors)
+
+		pager := kickClient.CategoriesPager(gokick.NewCategoryListFilter()).SetMaxItems(3)
+		ids := collectCategoryIDs(t, pager.All(context.Background()))
+		assert.Equal(t, []int{1, 2, 3}, ids)
+		assert.Equal(t, []string{"", "page-2"}, cursors)
+		assert.False(t, pager.HasNext())
+	})
+
+	t.Run("early break", func(t *testing.T) {
+		var cursors []string
+		kickClient := setupPaginatedCategoriesClient(t, &cursors)
+
+		for category, err := range kickClient.AllCategories(context.Background(), gokick.NewCategoryListFilter()) {
+			require.NoError(t, err)
+			if category.ID == 2 {
+				break
+			}
+		}
+		assert.Equal(t, []string{""}, cursors)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		kickClient := setupPaginatedCategoriesClient(t, nil)
+
+		var gotErr error
+		for _, err := range kickClient.AllCategories(context.Background(), gokick.NewCategoryListFilter().SetCursor("invalid")) {
+			gotErr = err
+		}
+		require.EqualError(t, gotErr, "Error 400: invalid cursor")
+	})
+
+	t.Run("context cancelled", func(t *testing.T) {
+		var cursors []string
+		kickClient := setupPaginatedCategoriesClient(t, &cursors)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		var gotErr error
+		for category, err := range kickClient.AllCategories(ctx, gokick.NewCategoryListFilter()) {
+			if err != nil {
+				gotErr = err
+				break
+			}
+			if category.ID == 2 {
+				cancel()
+			}
+		}
+		require.ErrorIs(t, gotErr, context.Canceled)
+		assert.Equal(t, []string{""}, cursors)
+	})
+}
+
+func TestPager(t *testing.T) {
+	t.Run("next page by page", func(t *testing.T) {
+		pager := gokick.NewPager(func(_ context.Context, cursor string) (gokick.PaginatedResponse[[]string], error) {
+			if cursor == "" {
+				return gokick.PaginatedResponse[[]string]{Result: []string{"a"}, Pagination: gokick.Pagination{NextCursor: "next"}}, nil
+			}
+			return gokick.PaginatedResponse[[]string]{Result: []string{"b"}}, nil
+		})
+
+		require.True(t, pager.HasNext())
+		items, err := pager.Next(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, []string{"a"}, items)
+
+		require.True(t, pager.HasNext())
+		items, err = pager.Next(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, []string{"b"}, items)
+
+		require.False(t, pager.HasNext())
+		items, err = pager.Next(context.Background())
+		require.NoError(t, err)
+		assert.Nil(t, items)
+	})
+
+	t.Run("stops when the cursor does not move", func(t *testing.T) {
+		calls := 0
+		pager := gokick.NewPager(func(_ context.Context, _ string) (gokick.PaginatedResponse[[]int], error) {
+			calls++
+			return gokick.PaginatedResponse[[]int]{Result: []int{calls}, Pagination: gokick.Pagination{NextCursor: "same"}}, nil
+		})
+
+		var items []int
+		for item, err := range pager.All(context.Background()) {
+			require.NoError(t, err)
+			items = append(items, item)
+		}
+		assert.Equal(t, []int{1, 2}, items)
+	})
+
+	t.Run("stops on an empty page", func(t *testing.T) {
+		calls := 0
+		pager := gokick.NewPager(func(_ context.Context, _ string) (gokick.PaginatedResponse[[]int], error) {
+			calls++
+			return gokick.PaginatedResponse[[]int]{Pagination: gokick.Pagination{NextCursor: fmt.Sprintf("%d", calls)}}, nil
+		})
+
+		for _, err := range pager.All(context.Background()) {
+			require.NoError(t, err)
+		}
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("fetch error", func(t *testing.T) {
+		pager := gokick.NewPager(func(_ context.Context, _ string) (gokick.PaginatedResponse[[]int], error) {
+			return gokick.PaginatedResponse[[]int]{}, errors.New("boom")
+		})
+
+		_, err := pager.Next(context.Background())
+		require.EqualError(t, err, "boom")
+	})
+}
@@ -0,0 +1,224 @@
+// Package gokickprom exposes Prometheus metrics for gokick API calls and webhook events.
+//
+// It lives in its own module so that applications not using Prometheus do not depend on it.
+package gokickprom
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/scorfly/gokick"
+)
+
+const defaultNamespace = "gokick"
+
+// CollectorOptions configures a Collector.
+type CollectorOptions struct {
+	// Namespace prefixes the metric names. Defaults to "gokick".
+	Namespace string
+	// ConstLabels are added to every metric, e.g. to tell applications apart.
+	ConstLabels prometheus.Labels
+	// Buckets are the buckets of the request duration histogram. Defaults to prometheus.DefBuckets.
+	Buckets []float64
+}
+
+// Collector is a prometheus.Collector counting the calls made by gokick clients and the webhook events received.
+// Plug it into clients with Middleware and into webhook handlers with WebhookHandler, then register it.
+type Collector struct {
+	requests          *prometheus.CounterVec
+	requestDuration   *prometheus.HistogramVec
+	retries           *prometheus.CounterVec
+	tokenRefreshes    *prometheus.CounterVec
+	webhookEvents     *prometheus.CounterVec
+	webhookErrors     *prometheus.CounterVec
+	signatureFailures prometheus.Counter
+}
+
+func NewCollector(options CollectorOptions) *Collector {
+	if options.Namespace == "" {
+		options.Namespace = defaultNamespace
+	}
+	if options.Buckets == nil {
+		options.Buckets = prometheus.DefBuckets
+	}
+
+	return &Collector{
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace:   options.Namespace,
+			Name:        "client_requests_total",
+			Help:        "Kick API calls, by endpoint, method and status code.",
+			ConstLabels: options.ConstLabels,
+		}, []string{"endpoint", "method", "status"}),
+		requestDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace:   options.Namespace,
+			Name:        "client_request_duration_seconds",
+			Help:        "Duration of Kick API calls, retries included, by endpoint, method and status code.",
+			ConstLabels: options.ConstLabels,
+			Buckets:     options.Buckets,
+		}, []string{"endpoint", "method", "status"}),
+		retries: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace:   options.Namespace,
+			Name:        "client_retries_total",
+			Help:        "Retried attempts of Kick API calls, by endpoint and method.",
+			ConstLabels: options.ConstLabels,
+		}, []string{"endpoint", "method"}),
+		tokenRefreshes: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace:   options.Namespace,
+			Name:        "token_refreshes_total",
+			Help:        "Token refreshes, by token kind and outcome.",
+			ConstLabels: options.ConstLabels,
+		}, []string{"kind", "outcome"}),
+		webhookEvents: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace:   options.Namespace,
+			Name:        "webhook_events_total",
+			Help:        "Webhook events received with a valid signature, by subscription name and version.",
+			ConstLabels: options.ConstLabels,
+		}, []string{"subscription_name", "version"}),
+		webhookErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace:   options.Namespace,
+			Name:        "webhook_errors_total",
+			Help:        "Webhook events that failed to be parsed or handled, by subscription name and stage.",
+			ConstLabels: options.ConstLabels,
+		}, []string{"subscription_name", "stage"}),
+		signatureFailures: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace:   options.Namespace,
+			Name:        "webhook_signature_failures_total",
+			Help:        "Webhook events rejected because their signature could not be verified.",
+			ConstLabels: options.ConstLabels,
+		}),
+	}
+}
+
+// Describe implements prometheus.Collector.
+func (c *Collector) Describe(descs chan<- *prometheus.Desc) {
+	for _, collector := range c.collectors() {
+		collector.Describe(descs)
+	}
+}
+
+// Collect implements prometheus.Collector.
+func (c *Collector) Collect(metrics chan<- prometheus.Metric) {
+	for _, collector := range c.collectors() {
+		collector.Collect(metrics)
+	}
+}
+
+func (c *Collector) collectors() []prometheus.Collector {
+	return []prometheus.Collector{
+		c.requests,
+		c.requestDuration,
+		c.retries,
+		c.tokenRefreshes,
+		c.webhookEvents,
+		c.webhookErrors,
+		c.signatureFailures,
+	}
+}
+
+// Middleware returns a gokick.Middleware counting the calls of a client, with their duration, retries and token
+// refreshes. Endpoints are labelled with their route (e.g. "/public/v1/chat/{message_id}"), and the status label
+// is "error" for calls that failed without response.
+func (c *Collector) Middleware() gokick.Middleware {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return gokick.RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			endpoint := gokick.Route(req.URL.Path)
+			ctx := req.Context()
+
+			hooks := chainTrace(gokick.ContextTrace(ctx))
+			parentRetry := hooks.Retry
+			hooks.Retry = func(attempt int, delay time.Duration, response *http.Response, err error) {
+				c.retries.WithLabelValues(endpoint, req.Method).Inc()
+				if parentRetry != nil {
+					parentRetry(attempt, delay, response, err)
+				}
+			}
+			parentTokenRefreshed := hooks.TokenRefreshed
+			hooks.TokenRefreshed = func(kind string, err error) {
+				c.tokenRefreshes.WithLabelValues(kind, outcome(err)).Inc()
+				if parentTokenRefreshed != nil {
+					parentTokenRefreshed(kind, err)
+				}
+			}
+
+			start := time.Now()
+			response, err := next.RoundTrip(req.WithContext(gokick.WithTrace(ctx, hooks)))
+
+			status := "error"
+			if err == nil {
+				status = strconv.Itoa(response.StatusCode)
+			}
+			c.requests.WithLabelValues(endpoint, req.Method, status).Inc()
+			c.requestDuration.WithLabelValues(endpoint, req.Method, status).Observe(time.Since(start).Seconds())
+
+			return response, err
+		})
+	}
+}
+
+// WebhookHandler wraps the HTTP handler receiving Kick webhooks, either a gokick.EventHandler or a handler calling
+// gokick.GetEventFromRequest, to count the events received, the signature failures and the events that failed to
+// be parsed or handled.
+func (c *Collector) WebhookHandler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var subscriptionName string
+
+		hooks := chainTrace(gokick.ContextTrace(r.Context()))
+		parentVerifyDone := hooks.WebhookVerifyDone
+		hooks.WebhookVerifyDone = func(err error) {
+			if err != nil {
+				c.signatureFailures.Inc()
+			}
+			if parentVerifyDone != nil {
+				parentVerifyDone(err)
+			}
+		}
+		parentParseStart := hooks.WebhookParseStart
+		hooks.WebhookParseStart = func(name gokick.SubscriptionName, version string) {
+			subscriptionName = name.String()
+			c.webhookEvents.WithLabelValues(subscriptionName, version).Inc()
+			if parentParseStart != nil {
+				parentParseStart(name, version)
+			}
+		}
+		parentParseDone := hooks.WebhookParseDone
+		hooks.WebhookParseDone = func(err error) {
+			if err != nil {
+				c.webhookErrors.WithLabelValues(subscriptionName, "parse").Inc()
+			}
+			if parentParseDone != nil {
+				parentParseDone(err)
+			}
+		}
+		parentDispatchDone := hooks.WebhookDispatchDone
+		hooks.WebhookDispatchDone = func(err error) {
+			if err != nil {
+				c.webhookErrors.WithLabelValues(subscriptionName, "dispatch").Inc()
+			}
+			if parentDispatchDone != nil {
+				parentDispatchDone(err)
+			}
+		}
+
+		next.ServeHTTP(w, r.WithContext(gokick.WithTrace(r.Context(), hooks)))
+	})
+}
+
+// chainTrace returns a copy of parent, or an empty Trace, whose hooks can be replaced by ones calling parent's.
+func chainTrace(parent *gokick.Trace) *gokick.Trace {
+	if parent == nil {
+		return &gokick.Trace{}
+	}
+
+	hooks := *parent
+	return &hooks
+}
+
+func outcome(err error) string {
+	if err != nil {
+		return "failure"
+	}
+
+	return "success"
+}
@@ -0,0 +1,202 @@
+package gokickprom_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/scorfly/gokick"
+	gokickprom "github.com/scorfly/gokick/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCollectorRegister(t *testing.T) {
+	registry := prometheus.NewPedanticRegistry()
+	require.NoError(t, registry.Register(gokickprom.NewCollector(gokickprom.CollectorOptions{})))
+
+	_, err := registry.Gather()
+	require.NoError(t, err)
+}
+
+func TestMiddleware(t *testing.T) {
+	collector := gokickprom.NewCollector(gokickprom.CollectorOptions{})
+
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/oauth/token":
+			fmt.Fprint(w, `{"access_token":"new-access","refresh_token":"new-refresh","expires_in":7200}`)
+		case r.Header.Get("Authorization") != "Bearer new-access":
+			w.WriteHeader(http.StatusUnauthorized)
+			fmt.Fprint(w, `{"message":"unauthorized"}`)
+		case calls.Add(1) == 1:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		case r.Method == http.MethodGet:
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"message":"not found"}`)
+		default:
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	kickClient, err := gokick.NewClient(&gokick.ClientOptions{
+		UserAccessToken:  "old-access",
+		UserRefreshToken: "old-refresh",
+		ClientID:         "client-id",
+		ClientSecret:     "client-secret",
+		APIBaseURL:       server.URL,
+		AuthBaseURL:      server.URL,
+		RetryPolicy:      &gokick.RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: 10 * time.Millisecond},
+		Middlewares:      []gokick.Middleware{collector.Middleware()},
+	})
+	require.NoError(t, err)
+
+	_, err = kickClient.DeleteChatMessage(context.Background(), "message-id")
+	require.NoError(t, err)
+
+	_, err = kickClient.GetUsers(context.Background(), gokick.NewUserListFilter())
+	require.ErrorIs(t, err, gokick.ErrNotFound)
+
+	expected := `
+# HELP gokick_client_requests_total Kick API calls, by endpoint, method and status code.
+# TYPE gokick_client_requests_total counter
+gokick_client_requests_total{endpoint="/oauth/token",method="POST",status="200"} 1
+gokick_client_requests_total{endpoint="/public/v1/chat/{message_id}",method="DELETE",status="204"} 1
+gokick_client_requests_total{endpoint="/public/v1/users",method="GET",status="404"} 1
+# HELP gokick_client_retries_total Retried attempts of Kick API calls, by endpoint and method.
+# TYPE gokick_client_retries_total counter
+gokick_client_retries_total{endpoint="/public/v1/chat/{message_id}",method="DELETE"} 1
+# HELP gokick_token_refreshes_total Token refreshes, by token kind and outcome.
+# TYPE gokick_token_refreshes_total counter
+gokick_token_refreshes_total{kind="user",outcome="success"} 1
+`
+	require.NoError(t, testutil.CollectAndCompare(collector, strings.NewReader(expected),
+		"gokick_client_requests_total", "gokick_client_retries_total", "gokick_token_refreshes_total"))
+
+	assert.Equal(t, 3, testutil.CollectAndCount(collector, "gokick_client_request_duration_seconds"))
+}
+
+func TestMiddlewareConcurrentTokenRefresh(t *testing.T) {
+	collector := gokickprom.NewCollector(gokickprom.CollectorOptions{})
+
+	var refreshes atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/oauth/token":
+			refreshes.Add(1)
+			fmt.Fprint(w, `{"access_token":"new-access","refresh_token":"new-refresh","expires_in":7200}`)
+		case r.Header.Get("Authorization") != "Bearer new-access":
+			w.WriteHeader(http.StatusUnauthorized)
+			fmt.Fprint(w, `{"message":"unauthorized"}`)
+		default:
+			fmt.Fprint(w, `{"data":[]}`)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	kickClient, err := gokick.NewClient(&gokick.ClientOptions{
+		UserAccessToken:  "old-access",
+		UserRefreshToken: "old-refresh",
+		ClientID:         "client-id",
+		ClientSecret:     "client-secret",
+		APIBaseURL:       server.URL,
+		AuthBaseURL:      server.URL,
+		Middlewares:      []gokick.Middleware{collector.Middleware()},
+	})
+	require.NoError(t, err)
+
+	var wg sync.WaitGroup
+	for range 20 {
+		wg.Go(func() {
+			_, err := kickClient.GetUsers(context.Background(), gokick.NewUserListFilter())
+			assert.NoError(t, err)
+		})
+	}
+	wg.Wait()
+
+	expected := `
+# HELP gokick_token_refreshes_total Token refreshes, by token kind and outcome.
+# TYPE gokick_token_refreshes_total counter
+gokick_token_refreshes_total{kind="user",outcome="success"} 1
+`
+	require.NoError(t, testutil.CollectAndCompare(collector, strings.NewReader(expected), "gokick_token_refreshes_total"))
+	assert.Equal(t, int32(1), refreshes.Load())
+}
+
+func TestMiddlewareNetworkError(t *testing.T) {
+	collector := gokickprom.NewCollector(gokickprom.CollectorOptions{Namespace: "kick"})
+
+	server := httptest.NewServer(http.NotFoundHandler())
+	server.Close()
+
+	kickClient, err := gokick.NewClient(&gokick.ClientOptions{
+		APIBaseURL:  server.URL,
+		Middlewares: []gokick.Middleware{collector.Middleware()},
+	})
+	require.NoError(t, err)
+
+	_, err = kickClient.GetUsers(context.Background(), gokick.NewUserListFilter())
+	require.Error(t, err)
+
+	expected := `
+# HELP kick_client_requests_total Kick API calls, by endpoint, method and status code.
+# TYPE kick_client_requests_total counter
+kick_client_requests_total{endpoint="/public/v1/users",method="GET",status="error"} 1
+`
+	require.NoError(t, testutil.CollectAndCompare(collector, strings.NewReader(expected), "kick_client_requests_total"))
+}
+
+func TestWebhookHandler(t *testing.T) {
+	collector := gokickprom.NewCollector(gokickprom.CollectorOptions{})
+
+	handler := gokick.NewEventHandler()
+	handler.OnChannelFollow(func(context.Context, *gokick.ChannelFollowEvent, gokick.EventMeta) error {
+		return fmt.Errorf("failed to store follow")
+	})
+	webhookHandler := collector.WebhookHandler(handler)
+
+	send := func(body string) int {
+		req := httptest.NewRequest(http.MethodPost, "/webhook", strings.NewReader(body))
+		req.Header.Set("Kick-Event-Type", "channel.followed")
+		req.Header.Set("Kick-Event-Version", "1")
+		req.Header.Set("Kick-Event-Message-Id", "message-id")
+
+		recorder := httptest.NewRecorder()
+		webhookHandler.ServeHTTP(recorder, req)
+
+		return recorder.Code
+	}
+
+	assert.Equal(t, http.StatusUnauthorized, send(`{"follower":{"user_id":1}}`))
+
+	gokick.SkipSignatureValidation = true
+	t.Cleanup(func() { gokick.SkipSignatureValidation = false })
+
+	assert.Equal(t, http.StatusBadRequest, send(`{`))
+	assert.Equal(t, http.StatusInternalServerError, send(`{"follower":{"user_id":1}}`))
+
+	expected := `
+# HELP gokick_webhook_errors_total Webhook events that failed to be parsed or handled, by subscription name and stage.
+# TYPE gokick_webhook_errors_total counter
+gokick_webhook_errors_total{stage="dispatch",subscription_name="channel.followed"} 1
+gokick_webhook_errors_total{stage="parse",subscription_name="channel.followed"} 1
+# HELP gokick_webhook_events_total Webhook events received with a valid signature, by subscription name and version.
+# TYPE gokick_webhook_events_total counter
+gokick_webhook_events_total{subscription_name="channel.followed",version="1"} 2
+# HELP gokick_webhook_signature_failures_total Webhook events rejected because their signature could not be verified.
+# TYPE gokick_webhook_signature_failures_total counter
+gokick_webhook_signature_failures_total 1
+`
+	require.NoError(t, testutil.CollectAndCompare(collector, strings.NewReader(expected),
+		"gokick_webhook_errors_total", "gokick_webhook_events_total", "gokick_webhook_signature_failures_total"))
+}
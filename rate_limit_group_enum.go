@@ -0,0 +1,42 @@
+package gokick
+
+import "fmt"
+
+type RateLimitGroup int
+
+const (
+	RateLimitGroupDefault    RateLimitGroup = iota // default
+	RateLimitGroupChat                             // chat
+	RateLimitGroupModeration                       // moderation
+	RateLimitGroupChannels                         // channels
+)
+
+func NewRateLimitGroup(group string) (RateLimitGroup, error) {
+	switch group {
+	case "default":
+		return RateLimitGroupDefault, nil
+	case "chat":
+		return RateLimitGroupChat, nil
+	case "moderation":
+		return RateLimitGroupModeration, nil
+	case "channels":
+		return RateLimitGroupChannels, nil
+	default:
+		return 0, fmt.Errorf("unknown rate limit group: %s", group)
+	}
+}
+
+func (g RateLimitGroup) String() string {
+	switch g {
+	case RateLimitGroupDefault:
+		return "default"
+	case RateLimitGroupChat:
+		return "chat"
+	case RateLimitGroupModeration:
+		return "moderation"
+	case RateLimitGroupChannels:
+		return "channels"
+	default:
+		return "unknown"
+	}
+}
@@ -0,0 +1,45 @@
+package gokick_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/scorfly/gokick"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewRateLimitGroupError(t *testing.T) {
+	testCases := map[string]string{
+		"empty":         "",
+		"not supported": "not supported",
+	}
+
+	for name, value := range testCases {
+		t.Run(name, func(t *testing.T) {
+			_, err := gokick.NewRateLimitGroup(value)
+			assert.EqualError(t, err, fmt.Sprintf("unknown rate limit group: %s", value))
+		})
+	}
+}
+
+func TestNewRateLimitGroupSuccess(t *testing.T) {
+	testCases := map[string]gokick.RateLimitGroup{
+		"default":    gokick.RateLimitGroupDefault,
+		"chat":       gokick.RateLimitGroupChat,
+		"moderation": gokick.RateLimitGroupModeration,
+		"channels":   gokick.RateLimitGroupChannels,
+	}
+
+	for name, value := range testCases {
+		t.Run(name, func(t *testing.T) {
+			group, err := gokick.NewRateLimitGroup(value.String())
+			require.NoError(t, err)
+			assert.Equal(t, group, value)
+		})
+	}
+}
+
+func TestRateLimitGroupStringUnknown(t *testing.T) {
+	assert.Equal(t, "unknown", gokick.RateLimitGroup(-1).String())
+}
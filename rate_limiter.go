@@ -0,0 +1,232 @@
+package gokick
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RateLimit describes a token bucket: Rate requests per second on average, with bursts of up to Burst requests.
+// A zero Rate disables the bucket; the group is then only throttled by rate-limit headers returned by Kick.
+type RateLimit struct {
+	Rate  float64
+	Burst int
+}
+
+// RateLimiter throttles outgoing requests per endpoint group (chat, moderation, channels, default).
+//
+// Each group has its own token bucket. The limiter also adapts to X-RateLimit-Remaining / X-RateLimit-Reset
+// and Retry-After headers: once Kick reports the quota as exhausted, callers of that group block until it resets.
+type RateLimiter struct {
+	mu      sync.Mutex
+	limits  map[RateLimitGroup]RateLimit
+	buckets map[RateLimitGroup]*tokenBucket
+}
+
+type tokenBucket struct {
+	limit        RateLimit
+	tokens       float64
+	last         time.Time
+	blockedUntil time.Time
+}
+
+// NewRateLimiter returns a limiter applying defaultLimit to every group without a dedicated limit.
+func NewRateLimiter(defaultLimit RateLimit) *RateLimiter {
+	return &RateLimiter{
+		limits:  map[RateLimitGroup]RateLimit{RateLimitGroupDefault: defaultLimit},
+		buckets: make(map[RateLimitGroup]*tokenBucket),
+	}
+}
+
+// SetLimit sets a dedicated limit for a group.
+func (l *RateLimiter) SetLimit(group RateLimitGroup, limit RateLimit) *RateLimiter {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.limits[group] = limit
+	delete(l.buckets, group)
+
+	return l
+}
+
+// clone returns a limiter with the limits of l and none of its state.
+func (l *RateLimiter) clone() *RateLimiter {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return &RateLimiter{
+		limits:  maps.Clone(l.limits),
+		buckets: make(map[RateLimitGroup]*tokenBucket),
+	}
+}
+
+// Wait blocks until the group has capacity for one request or ctx is done.
+func (l *RateLimiter) Wait(ctx context.Context, group RateLimitGroup) error {
+	for {
+		delay := l.reserve(group)
+		if delay == 0 {
+			return nil
+		}
+
+		err := sleepContext(ctx, delay)
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// reserve takes a token when one is available and returns 0, or returns how long to wait before trying again.
+func (l *RateLimiter) reserve(group RateLimitGroup) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	bucket := l.bucket(group, now)
+
+	if now.Before(bucket.blockedUntil) {
+		return bucket.blockedUntil.Sub(now)
+	}
+
+	if bucket.limit.Rate <= 0 {
+		return 0
+	}
+
+	bucket.refill(now)
+	if bucket.tokens >= 1 {
+		bucket.tokens--
+		return 0
+	}
+
+	return time.Duration(math.Ceil((1 - bucket.tokens) / bucket.limit.Rate * float64(time.Second)))
+}
+
+// update adapts the group to the rate-limit headers of a response.
+func (l *RateLimiter) update(group RateLimitGroup, response *http.Response) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	bucket := l.bucket(group, now)
+
+	if response.StatusCode == http.StatusTooManyRequests {
+		retryAfter, ok := parseRetryAfter(response.Header.Get("Retry-After"), now)
+		if ok {
+			bucket.block(now.Add(retryAfter))
+		}
+	}
+
+	remaining, err := strconv.Atoi(response.Header.Get("X-RateLimit-Remaining"))
+	if err != nil {
+		return
+	}
+
+	if bucket.limit.Rate > 0 {
+		bucket.refill(now)
+		bucket.tokens = math.Min(bucket.tokens, float64(remaining))
+	}
+
+	if remaining > 0 {
+		return
+	}
+
+	reset, ok := parseRateLimitReset(response.Header.Get("X-RateLimit-Reset"), now)
+	if ok {
+		bucket.block(reset)
+	}
+}
+
+func (l *RateLimiter) bucket(group RateLimitGroup, now time.Time) *tokenBucket {
+	bucket, ok := l.buckets[group]
+	if ok {
+		return bucket
+	}
+
+	limit, ok := l.limits[group]
+	if !ok {
+		limit = l.limits[RateLimitGroupDefault]
+	}
+
+	bucket = &tokenBucket{
+		limit:  limit,
+		tokens: float64(max(limit.Burst, 1)),
+		last:   now,
+	}
+	l.buckets[group] = bucket
+
+	return bucket
+}
+
+func (b *tokenBucket) refill(now time.Time) {
+	elapsed := now.Sub(b.last).Seconds()
+	b.last = now
+
+	if elapsed > 0 {
+		b.tokens = math.Min(b.tokens+elapsed*b.limit.Rate, float64(max(b.limit.Burst, 1)))
+	}
+}
+
+func (b *tokenBucket) block(until time.Time) {
+	if until.After(b.blockedUntil) {
+		b.blockedUntil = until
+	}
+}
+
+// parseRateLimitReset parses X-RateLimit-Reset, which holds either a number of seconds or a Unix timestamp.
+func parseRateLimitReset(value string, now time.Time) (time.Time, bool) {
+	if value == "" {
+		return time.Time{}, false
+	}
+
+	seconds, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || seconds < 0 {
+		return time.Time{}, false
+	}
+
+	// Values beyond a year are Unix timestamps rather than a delay.
+	if seconds > int64((365 * 24 * time.Hour).Seconds()) {
+		return time.Unix(seconds, 0), true
+	}
+
+	return now.Add(time.Duration(seconds) * time.Second), true
+}
+
+// rateLimitGroupFromPath maps an API path to its rate-limit group.
+func rateLimitGroupFromPath(path string) RateLimitGroup {
+	switch {
+	case strings.HasPrefix(path, "/public/v1/chat"):
+		return RateLimitGroupChat
+	case strings.HasPrefix(path, "/public/v1/moderation"):
+		return RateLimitGroupModeration
+	case strings.HasPrefix(path, "/public/v1/channels"):
+		return RateLimitGroupChannels
+	default:
+		return RateLimitGroupDefault
+	}
+}
+
+func (c *Client) waitRateLimit(req *http.Request) error {
+	if c.options.RateLimiter == nil {
+		return nil
+	}
+
+	err := c.options.RateLimiter.Wait(req.Context(), rateLimitGroupFromPath(req.URL.Path))
+	if err != nil {
+		return fmt.Errorf("failed to wait for rate limiter: %w", err)
+	}
+
+	return nil
+}
+
+func (c *Client) updateRateLimit(req *http.Request, response *http.Response) {
+	if c.options.RateLimiter == nil {
+		return
+	}
+
+	c.options.RateLimiter.update(rateLimitGroupFromPath(req.URL.Path), response)
+}
@@ -0,0 +1,157 @@
+package gokick_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/scorfly/gokick"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRateLimiterWait(t *testing.T) {
+	t.Run("burst then throttled", func(t *testing.T) {
+		limiter := gokick.NewRateLimiter(gokick.RateLimit{Rate: 20, Burst: 2})
+
+		start := time.Now()
+		for range 3 {
+			require.NoError(t, limiter.Wait(context.Background(), gokick.RateLimitGroupDefault))
+		}
+
+		assert.GreaterOrEqual(t, time.Since(start), 40*time.Millisecond)
+	})
+
+	t.Run("unlimited", func(t *testing.T) {
+		limiter := gokick.NewRateLimiter(gokick.RateLimit{})
+
+		for range 100 {
+			require.NoError(t, limiter.Wait(context.Background(), gokick.RateLimitGroupDefault))
+		}
+	})
+
+	t.Run("groups are independent", func(t *testing.T) {
+		limiter := gokick.NewRateLimiter(gokick.RateLimit{}).
+			SetLimit(gokick.RateLimitGroupChat, gokick.RateLimit{Rate: 0.001, Burst: 1})
+
+		require.NoError(t, limiter.Wait(context.Background(), gokick.RateLimitGroupChat))
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		require.ErrorIs(t, limiter.Wait(ctx, gokick.RateLimitGroupChat), context.DeadlineExceeded)
+		require.NoError(t, limiter.Wait(context.Background(), gokick.RateLimitGroupModeration))
+	})
+}
+
+func TestClientRateLimiterError(t *testing.T) {
+	t.Run("context cancelled while waiting", func(t *testing.T) {
+		limiter := gokick.NewRateLimiter(gokick.RateLimit{Rate: 0.001, Burst: 1})
+		options := gokick.ClientOptions{UserAccessToken: "access-token", RateLimiter: limiter}
+		kickClient := setupMockClientWithOptions(t, options, func(w http.ResponseWriter, _ *http.Request) {
+			fmt.Fprint(w, `{"data":[]}`)
+		})
+
+		_, err := kickClient.GetUsers(context.Background(), gokick.NewUserListFilter())
+		require.NoError(t, err)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		_, err = kickClient.GetUsers(ctx, gokick.NewUserListFilter())
+		require.EqualError(t, err, "failed to make request: failed to wait for rate limiter: context deadline exceeded")
+	})
+
+	t.Run("quota exhausted by headers", func(t *testing.T) {
+		limiter := gokick.NewRateLimiter(gokick.RateLimit{})
+		options := gokick.ClientOptions{UserAccessToken: "access-token", RateLimiter: limiter}
+		kickClient := setupMockClientWithOptions(t, options, func(w http.ResponseWriter, _ *http.Request) {
+			w.Header().Set("X-RateLimit-Remaining", "0")
+			w.Header().Set("X-RateLimit-Reset", "60")
+			fmt.Fprint(w, `{"data":{"is_sent":true,"message_id":"id"}}`)
+		})
+
+		_, err := kickClient.SendChatMessage(context.Background(), nil, "hello", nil, gokick.MessageTypeUser)
+		require.NoError(t, err)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		_, err = kickClient.SendChatMessage(ctx, nil, "hello", nil, gokick.MessageTypeUser)
+		require.EqualError(t, err, "failed to make request: failed to wait for rate limiter: context deadline exceeded")
+	})
+
+	t.Run("quota exhausted until a Unix timestamp", func(t *testing.T) {
+		limiter := gokick.NewRateLimiter(gokick.RateLimit{})
+		options := gokick.ClientOptions{UserAccessToken: "access-token", RateLimiter: limiter}
+		kickClient := setupMockClientWithOptions(t, options, func(w http.ResponseWriter, _ *http.Request) {
+			w.Header().Set("X-RateLimit-Remaining", "0")
+			w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(time.Now().Add(time.Minute).Unix(), 10))
+			fmt.Fprint(w, `{}`)
+		})
+
+		_, err := kickClient.BanUser(context.Background(), 1, 2, nil, nil)
+		require.NoError(t, err)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		_, err = kickClient.BanUser(ctx, 1, 2, nil, nil)
+		require.EqualError(t, err, "failed to make request: failed to wait for rate limiter: context deadline exceeded")
+	})
+
+	t.Run("rate limited with Retry-After", func(t *testing.T) {
+		limiter := gokick.NewRateLimiter(gokick.RateLimit{})
+		options := gokick.ClientOptions{UserAccessToken: "access-token", RateLimiter: limiter}
+		kickClient := setupMockClientWithOptions(t, options, func(w http.ResponseWriter, _ *http.Request) {
+			w.Header().Set("Retry-After", "60")
+			w.WriteHeader(http.StatusTooManyRequests)
+			fmt.Fprint(w, `{"message":"too many requests", "data":{}}`)
+		})
+
+		_, err := kickClient.GetChannels(context.Background(), gokick.NewChannelListFilter())
+		require.EqualError(t, err, "Error 429: too many requests")
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		_, err = kickClient.GetChannels(ctx, gokick.NewChannelListFilter())
+		require.EqualError(t, err, "failed to make request: failed to wait for rate limiter: context deadline exceeded")
+	})
+}
+
+func TestClientRateLimiterSuccess(t *testing.T) {
+	t.Run("remaining quota caps the bucket", func(t *testing.T) {
+		var calls atomic.Int32
+		limiter := gokick.NewRateLimiter(gokick.RateLimit{Rate: 1000, Burst: 10})
+		options := gokick.ClientOptions{UserAccessToken: "access-token", RateLimiter: limiter}
+		kickClient := setupMockClientWithOptions(t, options, func(w http.ResponseWriter, _ *http.Request) {
+			w.Header().Set("X-RateLimit-Remaining", strconv.Itoa(int(5-calls.Add(1))))
+			w.Header().Set("X-RateLimit-Reset", "1")
+			fmt.Fprint(w, `{"data":[]}`)
+		})
+
+		start := time.Now()
+		for range 5 {
+			_, err := kickClient.GetUsers(context.Background(), gokick.NewUserListFilter())
+			require.NoError(t, err)
+		}
+		assert.Equal(t, int32(5), calls.Load())
+
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+
+		_, err := kickClient.GetUsers(ctx, gokick.NewUserListFilter())
+		require.ErrorIs(t, err, context.DeadlineExceeded, "the quota is exhausted until X-RateLimit-Reset")
+		assert.Equal(t, int32(5), calls.Load())
+
+		_, err = kickClient.GetUsers(context.Background(), gokick.NewUserListFilter())
+		require.NoError(t, err)
+		assert.GreaterOrEqual(t, time.Since(start), time.Second)
+		assert.Equal(t, int32(6), calls.Load())
+	})
+}
@@ -6,50 +6,61 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"time"
 )
 
-func kickErrorFromResponse(statusCode int, responseBody []byte) error {
+func kickErrorFromResponse(resp *http.Response, responseBody []byte) error {
+	kickErr := newErrorFromResponse(resp, responseBody)
+
 	var apiErr errorResponse
 	apiUnmarshalErr := json.Unmarshal(responseBody, &apiErr)
 	if apiUnmarshalErr != nil {
-		return fmt.Errorf(
-			"failed to unmarshal error response (KICK status code: %d and body %q): %v",
-			statusCode,
-			string(responseBody),
-			apiUnmarshalErr,
-		)
+		return undecodedError{
+			err: fmt.Errorf(
+				"failed to unmarshal error response (KICK status code: %d and body %q): %w",
+				resp.StatusCode,
+				string(responseBody),
+				apiUnmarshalErr,
+			),
+			kickErr: kickErr,
+		}
 	}
 	if apiErr.Message != "" {
-		return NewError(statusCode, apiErr.Message)
+		kickErr.message = apiErr.Message
+		kickErr.fields = validationFields(apiErr.Data)
+		return kickErr
 	}
 
 	var oauthErr authErrorResponse
 	oauthUnmarshalErr := json.Unmarshal(responseBody, &oauthErr)
 	if oauthUnmarshalErr != nil {
-		return fmt.Errorf(
-			"failed to unmarshal error response (KICK status code: %d and body %q): %v",
-			statusCode,
-			string(responseBody),
-			oauthUnmarshalErr,
-		)
+		return undecodedError{
+			err: fmt.Errorf(
+				"failed to unmarshal error response (KICK status code: %d and body %q): %w",
+				resp.StatusCode,
+				string(responseBody),
+				oauthUnmarshalErr,
+			),
+			kickErr: kickErr,
+		}
 	}
 	msg := oauthErr.Message
 	if msg == "" {
 		msg = oauthErr.Error
 	}
 	if msg != "" {
-		e := NewError(statusCode, msg)
-		if oauthErr.ErrorDescription != "" {
-			e = e.WithDescription(oauthErr.ErrorDescription)
-		}
-		return e
+		kickErr.message = msg
+		return kickErr.WithDescription(oauthErr.ErrorDescription)
 	}
 
-	return fmt.Errorf(
-		"failed to unmarshal error response (KICK status code: %d and body %q): empty error message",
-		statusCode,
-		string(responseBody),
-	)
+	return undecodedError{
+		err: fmt.Errorf(
+			"failed to unmarshal error response (KICK status code: %d and body %q): empty error message",
+			resp.StatusCode,
+			string(responseBody),
+		),
+		kickErr: kickErr,
+	}
 }
 
 func makeRequest[T any](
@@ -76,28 +87,31 @@ func makeRequestWithBaseURL[T any](
 
 	req, err := http.NewRequestWithContext(ctx, method, url, body)
 	if err != nil {
-		return Response[T]{}, fmt.Errorf("failed to create request: %v", err)
+		return Response[T]{}, fmt.Errorf("failed to create request: %w", err)
 	}
 
 	req.Header.Set("Content-Type", "application/json")
 
-	resp, err := request.do(req)
+	start := time.Now()
+	resp, err := request.transport.RoundTrip(req)
 	if err != nil {
-		return Response[T]{}, fmt.Errorf("failed to make request: %v", err)
+		return Response[T]{}, fmt.Errorf("failed to make request: %w", err)
 	}
 	defer resp.Body.Close()
 
+	meta := newResponseMeta(resp, time.Since(start))
+
 	if resp.StatusCode == http.StatusNoContent {
-		return Response[T]{}, nil
+		return Response[T]{Meta: meta}, nil
 	}
 
 	responseBody, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return Response[T]{}, fmt.Errorf("failed to read response body (KICK status code %d): %v", resp.StatusCode, err)
+		return Response[T]{}, fmt.Errorf("failed to read response body (KICK status code %d): %w", resp.StatusCode, err)
 	}
 
 	if resp.StatusCode != statusCode {
-		return Response[T]{}, kickErrorFromResponse(resp.StatusCode, responseBody)
+		return Response[T]{}, kickErrorFromResponse(resp, responseBody)
 	}
 
 	type successResponse struct {
@@ -109,11 +123,11 @@ func makeRequestWithBaseURL[T any](
 	err = json.Unmarshal(responseBody, &success)
 	if err != nil {
 		return Response[T]{}, fmt.Errorf(
-			"failed to unmarshal response body (KICK status code %d and body %q): %v", resp.StatusCode, string(responseBody), err,
+			"failed to unmarshal response body (KICK status code %d and body %q): %w", resp.StatusCode, string(responseBody), err,
 		)
 	}
 
-	return Response[T](success), nil
+	return Response[T]{Result: success.Result, Meta: meta}, nil
 }
 
 func makePaginatedRequest[T any](
@@ -140,28 +154,31 @@ func makePaginatedRequestWithBaseURL[T any](
 
 	req, err := http.NewRequestWithContext(ctx, method, url, body)
 	if err != nil {
-		return PaginatedResponse[T]{}, fmt.Errorf("failed to create request: %v", err)
+		return PaginatedResponse[T]{}, fmt.Errorf("failed to create request: %w", err)
 	}
 
 	req.Header.Set("Content-Type", "application/json")
 
-	resp, err := request.do(req)
+	start := time.Now()
+	resp, err := request.transport.RoundTrip(req)
 	if err != nil {
-		return PaginatedResponse[T]{}, fmt.Errorf("failed to make request: %v", err)
+		return PaginatedResponse[T]{}, fmt.Errorf("failed to make request: %w", err)
 	}
 	defer resp.Body.Close()
 
+	meta := newResponseMeta(resp, time.Since(start))
+
 	if resp.StatusCode == http.StatusNoContent {
-		return PaginatedResponse[T]{}, nil
+		return PaginatedResponse[T]{Meta: meta}, nil
 	}
 
 	responseBody, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return PaginatedResponse[T]{}, fmt.Errorf("failed to read response body (KICK status code %d): %v", resp.StatusCode, err)
+		return PaginatedResponse[T]{}, fmt.Errorf("failed to read response body (KICK status code %d): %w", resp.StatusCode, err)
 	}
 
 	if resp.StatusCode != statusCode {
-		return PaginatedResponse[T]{}, kickErrorFromResponse(resp.StatusCode, responseBody)
+		return PaginatedResponse[T]{}, kickErrorFromResponse(resp, responseBody)
 	}
 
 	var success PaginatedResponse[T]
@@ -169,10 +186,12 @@ func makePaginatedRequestWithBaseURL[T any](
 	err = json.Unmarshal(responseBody, &success)
 	if err != nil {
 		return PaginatedResponse[T]{}, fmt.Errorf(
-			"failed to unmarshal response body (KICK status code %d and body %q): %v", resp.StatusCode, string(responseBody), err,
+			"failed to unmarshal response body (KICK status code %d and body %q): %w", resp.StatusCode, string(responseBody), err,
 		)
 	}
 
+	success.Meta = meta
+
 	return success, nil
 }
 
@@ -189,14 +208,14 @@ func makeAuthRequest[T any](
 	var response T
 	req, err := http.NewRequestWithContext(ctx, method, url, body)
 	if err != nil {
-		return response, fmt.Errorf("failed to create request: %v", err)
+		return response, fmt.Errorf("failed to create request: %w", err)
 	}
 
 	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
 
-	resp, err := request.do(req)
+	resp, err := request.transport.RoundTrip(req)
 	if err != nil {
-		return response, fmt.Errorf("failed to make request: %v", err)
+		return response, fmt.Errorf("failed to make request: %w", err)
 	}
 	defer resp.Body.Close()
 
@@ -206,33 +225,40 @@ func makeAuthRequest[T any](
 
 	responseBody, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return response, fmt.Errorf("failed to read response body (KICK status code %d): %v", resp.StatusCode, err)
+		return response, fmt.Errorf("failed to read response body (KICK status code %d): %w", resp.StatusCode, err)
 	}
 
 	if resp.StatusCode != statusCode {
+		kickErr := newErrorFromResponse(resp, responseBody)
+
 		var errorOutput authErrorResponse
 
 		err = json.Unmarshal(responseBody, &errorOutput)
 		if err != nil {
-			return response, fmt.Errorf(
-				"failed to unmarshal error response (KICK status code: %d and body %q): %v",
-				resp.StatusCode,
-				string(responseBody),
-				err,
-			)
+			return response, undecodedError{
+				err: fmt.Errorf(
+					"failed to unmarshal error response (KICK status code: %d and body %q): %w",
+					resp.StatusCode,
+					string(responseBody),
+					err,
+				),
+				kickErr: kickErr,
+			}
 		}
 
 		if errorOutput.Message != "" {
-			return response, NewError(resp.StatusCode, errorOutput.Message)
+			kickErr.message = errorOutput.Message
+			return response, kickErr
 		} else {
-			return response, NewError(resp.StatusCode, errorOutput.Error).WithDescription(errorOutput.ErrorDescription)
+			kickErr.message = errorOutput.Error
+			return response, kickErr.WithDescription(errorOutput.ErrorDescription)
 		}
 	}
 
 	err = json.Unmarshal(responseBody, &response)
 	if err != nil {
 		return response, fmt.Errorf(
-			"failed to unmarshal response body (KICK status code %d and body %q): %v", resp.StatusCode, string(responseBody), err,
+			"failed to unmarshal response body (KICK status code %d and body %q): %w", resp.StatusCode, string(responseBody), err,
 		)
 	}
 
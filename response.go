@@ -1,7 +1,14 @@
 package gokick
 
+import (
+	"net/http"
+	"strconv"
+	"time"
+)
+
 type Response[T any] struct {
 	Result T
+	Meta   ResponseMeta
 }
 
 // Pagination carries cursor-based pagination metadata from Kick API v2 list endpoints.
@@ -11,8 +18,58 @@ type Pagination struct {
 
 // PaginatedResponse is like Response but includes pagination (e.g. GET /public/v2/categories).
 type PaginatedResponse[T any] struct {
-	Result     T          `json:"data"`
-	Pagination Pagination `json:"pagination"`
+	Result     T            `json:"data"`
+	Pagination Pagination   `json:"pagination"`
+	Meta       ResponseMeta `json:"-"`
 }
 
 type EmptyResponse struct{}
+
+// ResponseMeta describes the HTTP exchange behind a Response: status, request ID, rate-limit headers and latency.
+type ResponseMeta struct {
+	StatusCode int
+	// RequestID is the value of the X-Request-Id header, when Kick sends one.
+	RequestID string
+	RateLimit RateLimitInfo
+	// Latency is the time spent in the HTTP exchange, including retries and token refreshes.
+	Latency time.Duration
+	Header  http.Header
+}
+
+// RateLimitInfo holds the X-RateLimit-* headers of a response. Fields are nil/zero when the header is absent.
+type RateLimitInfo struct {
+	Limit     *int
+	Remaining *int
+	Reset     time.Time
+}
+
+func newResponseMeta(response *http.Response, latency time.Duration) ResponseMeta {
+	return ResponseMeta{
+		StatusCode: response.StatusCode,
+		RequestID:  response.Header.Get("X-Request-Id"),
+		RateLimit:  newRateLimitInfo(response.Header, time.Now()),
+		Latency:    latency,
+		Header:     response.Header,
+	}
+}
+
+func newRateLimitInfo(header http.Header, now time.Time) RateLimitInfo {
+	var info RateLimitInfo
+
+	limit, err := strconv.Atoi(header.Get("X-RateLimit-Limit"))
+	if err == nil {
+		info.Limit = &limit
+	}
+
+	remaining, err := strconv.Atoi(header.Get("X-RateLimit-Remaining"))
+	if err == nil {
+		info.Remaining = &remaining
+	}
+
+	reset, ok := parseRateLimitReset(header.Get("X-RateLimit-Reset"), now)
+	if ok {
+		info.Reset = reset
+	}
+
+	return info
+}
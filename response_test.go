@@ -0,0 +1,68 @@
+package gokick_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/scorfly/gokick"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResponseMeta(t *testing.T) {
+	t.Run("response", func(t *testing.T) {
+		reset := time.Now().Add(time.Hour).Truncate(time.Second)
+		kickClient := setupMockClient(t, func(w http.ResponseWriter, _ *http.Request) {
+			w.Header().Set("X-Request-Id", "request-id")
+			w.Header().Set("X-RateLimit-Limit", "100")
+			w.Header().Set("X-RateLimit-Remaining", "42")
+			w.Header().Set("X-RateLimit-Reset", fmt.Sprintf("%d", reset.Unix()))
+			fmt.Fprint(w, `{"data":[{"user_id":117}]}`)
+		})
+
+		response, err := kickClient.GetUsers(context.Background(), gokick.NewUserListFilter())
+		require.NoError(t, err)
+
+		assert.Equal(t, http.StatusOK, response.Meta.StatusCode)
+		assert.Equal(t, "request-id", response.Meta.RequestID)
+		require.NotNil(t, response.Meta.RateLimit.Limit)
+		assert.Equal(t, 100, *response.Meta.RateLimit.Limit)
+		require.NotNil(t, response.Meta.RateLimit.Remaining)
+		assert.Equal(t, 42, *response.Meta.RateLimit.Remaining)
+		assert.True(t, reset.Equal(response.Meta.RateLimit.Reset))
+		assert.Positive(t, response.Meta.Latency)
+		assert.Equal(t, "request-id", response.Meta.Header.Get("X-Request-Id"))
+	})
+
+	t.Run("paginated response", func(t *testing.T) {
+		kickClient := setupMockClient(t, func(w http.ResponseWriter, _ *http.Request) {
+			w.Header().Set("X-Request-Id", "request-id")
+			fmt.Fprint(w, `{"data":[{"id":117,"name":"ok","thumbnail":"t"}],"pagination":{"next_cursor":""}}`)
+		})
+
+		categories, err := kickClient.GetCategories(context.Background(), gokick.NewCategoryListFilter())
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, categories.Meta.StatusCode)
+		assert.Equal(t, "request-id", categories.Meta.RequestID)
+		assert.Nil(t, categories.Meta.RateLimit.Limit)
+		assert.Nil(t, categories.Meta.RateLimit.Remaining)
+		assert.True(t, categories.Meta.RateLimit.Reset.IsZero())
+
+		category, err := kickClient.GetCategory(context.Background(), 117)
+		require.NoError(t, err)
+		assert.Equal(t, "request-id", category.Meta.RequestID)
+	})
+
+	t.Run("no content", func(t *testing.T) {
+		kickClient := setupMockClient(t, func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusNoContent)
+		})
+
+		response, err := kickClient.GetChannels(context.Background(), gokick.NewChannelListFilter())
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusNoContent, response.Meta.StatusCode)
+	})
+}
@@ -0,0 +1,151 @@
+package gokick
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy configures how Client retries requests that failed with a transient error.
+//
+// Rate-limited responses (429) are always retried because Kick did not process the request.
+// Server errors (5xx) and network errors are only retried for idempotent methods
+// (GET, HEAD, OPTIONS, PUT, DELETE), unless RetryNonIdempotent is set.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts, including the first one. Values below 2 disable retries.
+	MaxAttempts int
+	// BaseDelay is the backoff delay before the first retry; it doubles on every attempt.
+	BaseDelay time.Duration
+	// MaxDelay caps the backoff delay. A Retry-After header asking to wait longer than MaxDelay
+	// is not honored and the response is returned to the caller instead.
+	MaxDelay time.Duration
+	// RetryNonIdempotent allows POST and PATCH requests to be retried on server and network errors.
+	RetryNonIdempotent bool
+}
+
+// DefaultRetryPolicy returns a policy with 3 attempts and a backoff between 500ms and 10s.
+func DefaultRetryPolicy() *RetryPolicy {
+	return &RetryPolicy{
+		MaxAttempts: 3,
+		BaseDelay:   500 * time.Millisecond,
+		MaxDelay:    10 * time.Second,
+	}
+}
+
+// retryDelay reports whether the request should be attempted again and how long to wait before doing so.
+// attempt is the number of attempts already made.
+func (p *RetryPolicy) retryDelay(req *http.Request, attempt int, response *http.Response, err error) (time.Duration, bool) {
+	if p == nil || attempt >= p.MaxAttempts {
+		return 0, false
+	}
+
+	if err != nil {
+		if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+			return 0, false
+		}
+
+		if !p.canRetryMethod(req.Method) {
+			return 0, false
+		}
+
+		return p.backoff(attempt), true
+	}
+
+	switch response.StatusCode {
+	case http.StatusTooManyRequests:
+	case http.StatusInternalServerError,
+		http.StatusBadGateway,
+		http.StatusServiceUnavailable,
+		http.StatusGatewayTimeout:
+		if !p.canRetryMethod(req.Method) {
+			return 0, false
+		}
+	default:
+		return 0, false
+	}
+
+	if retryAfter, ok := parseRetryAfter(response.Header.Get("Retry-After"), time.Now()); ok {
+		if p.MaxDelay > 0 && retryAfter > p.MaxDelay {
+			return 0, false
+		}
+
+		return retryAfter, true
+	}
+
+	return p.backoff(attempt), true
+}
+
+func (p *RetryPolicy) canRetryMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	default:
+		return p.RetryNonIdempotent
+	}
+}
+
+// backoff returns a "full jitter" exponential delay: a random duration between 0 and BaseDelay*2^(attempt-1),
+// capped at MaxDelay.
+func (p *RetryPolicy) backoff(attempt int) time.Duration {
+	delay := p.BaseDelay
+	for i := 1; i < attempt && (p.MaxDelay <= 0 || delay < p.MaxDelay); i++ {
+		delay *= 2
+	}
+
+	if p.MaxDelay > 0 && delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+
+	if delay <= 0 {
+		return 0
+	}
+
+	return rand.N(delay + 1)
+}
+
+// parseRetryAfter parses a Retry-After header holding either a number of seconds or an HTTP date.
+func parseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+
+	seconds, err := strconv.Atoi(value)
+	if err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	date, err := http.ParseTime(value)
+	if err != nil {
+		return 0, false
+	}
+
+	delay := date.Sub(now)
+	if delay < 0 {
+		delay = 0
+	}
+
+	return delay, true
+}
+
+func sleepContext(ctx context.Context, delay time.Duration) error {
+	if delay <= 0 {
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,246 @@
+package gokick_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/scorfly/gokick"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func retryClientOptions(policy *gokick.RetryPolicy) gokick.ClientOptions {
+	return gokick.ClientOptions{UserAccessToken: "access-token", RetryPolicy: policy}
+}
+
+func fastRetryPolicy() *gokick.RetryPolicy {
+	return &gokick.RetryPolicy{
+		MaxAttempts: 3,
+		BaseDelay:   time.Millisecond,
+		MaxDelay:    10 * time.Millisecond,
+	}
+}
+
+func TestRetryPolicySuccess(t *testing.T) {
+	t.Run("server error on GET", func(t *testing.T) {
+		var calls atomic.Int32
+		kickClient := setupMockClientWithOptions(t, retryClientOptions(fastRetryPolicy()), func(w http.ResponseWriter, _ *http.Request) {
+			if calls.Add(1) == 1 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				fmt.Fprint(w, `{"message":"unavailable", "data":{}}`)
+				return
+			}
+			fmt.Fprint(w, `{"data":[{"user_id":117}]}`)
+		})
+
+		response, err := kickClient.GetUsers(context.Background(), gokick.NewUserListFilter())
+		require.NoError(t, err)
+		require.Len(t, response.Result, 1)
+		assert.Equal(t, int32(2), calls.Load())
+	})
+
+	t.Run("rate limited POST replays the body", func(t *testing.T) {
+		var calls atomic.Int32
+		var bodies []string
+		kickClient := setupMockClientWithOptions(t, retryClientOptions(fastRetryPolicy()), func(w http.ResponseWriter, r *http.Request) {
+			body, err := io.ReadAll(r.Body)
+			assert.NoError(t, err)
+			bodies = append(bodies, string(body))
+
+			if calls.Add(1) == 1 {
+				w.Header().Set("Retry-After", "0")
+				w.WriteHeader(http.StatusTooManyRequests)
+				fmt.Fprint(w, `{"message":"too many requests", "data":{}}`)
+				return
+			}
+			fmt.Fprint(w, `{"data":{"is_sent":true,"message_id":"id"}}`)
+		})
+
+		response, err := kickClient.SendChatMessage(context.Background(), nil, "hello", nil, gokick.MessageTypeUser)
+		require.NoError(t, err)
+		assert.True(t, response.Result.IsSent)
+		require.Len(t, bodies, 2)
+		assert.Equal(t, bodies[0], bodies[1])
+		assert.NotEmpty(t, bodies[0])
+	})
+
+	t.Run("server error on POST when non-idempotent retries are allowed", func(t *testing.T) {
+		var calls atomic.Int32
+		policy := fastRetryPolicy()
+		policy.RetryNonIdempotent = true
+		kickClient := setupMockClientWithOptions(t, retryClientOptions(policy), func(w http.ResponseWriter, _ *http.Request) {
+			if calls.Add(1) == 1 {
+				w.WriteHeader(http.StatusBadGateway)
+				fmt.Fprint(w, `{"message":"bad gateway", "data":{}}`)
+				return
+			}
+			fmt.Fprint(w, `{"data":{"is_sent":true,"message_id":"id"}}`)
+		})
+
+		_, err := kickClient.SendChatMessage(context.Background(), nil, "hello", nil, gokick.MessageTypeUser)
+		require.NoError(t, err)
+		assert.Equal(t, int32(2), calls.Load())
+	})
+
+	t.Run("Retry-After HTTP date", func(t *testing.T) {
+		var calls atomic.Int32
+		kickClient := setupMockClientWithOptions(t, retryClientOptions(fastRetryPolicy()), func(w http.ResponseWriter, _ *http.Request) {
+			if calls.Add(1) == 1 {
+				w.Header().Set("Retry-After", time.Now().Add(-time.Minute).UTC().Format(http.TimeFormat))
+				w.WriteHeader(http.StatusTooManyRequests)
+				fmt.Fprint(w, `{"message":"too many requests", "data":{}}`)
+				return
+			}
+			fmt.Fprint(w, `{"data":[]}`)
+		})
+
+		_, err := kickClient.GetUsers(context.Background(), gokick.NewUserListFilter())
+		require.NoError(t, err)
+		assert.Equal(t, int32(2), calls.Load())
+	})
+
+	t.Run("network error", func(t *testing.T) {
+		transport := &flakyRoundTripper{failures: 1}
+		kickClient, err := gokick.NewClient(&gokick.ClientOptions{
+			UserAccessToken: "access-token",
+			HTTPClient:      &http.Client{Transport: transport},
+			RetryPolicy:     fastRetryPolicy(),
+		})
+		require.NoError(t, err)
+
+		_, err = kickClient.GetUsers(context.Background(), gokick.NewUserListFilter())
+		require.NoError(t, err)
+		assert.Equal(t, 2, transport.calls)
+	})
+
+	t.Run("default policy", func(t *testing.T) {
+		policy := gokick.DefaultRetryPolicy()
+		assert.Equal(t, 3, policy.MaxAttempts)
+		assert.Equal(t, 500*time.Millisecond, policy.BaseDelay)
+		assert.Equal(t, 10*time.Second, policy.MaxDelay)
+		assert.False(t, policy.RetryNonIdempotent)
+	})
+}
+
+func TestRetryPolicyError(t *testing.T) {
+	t.Run("no policy", func(t *testing.T) {
+		var calls atomic.Int32
+		kickClient := setupMockClientWithOptions(t, retryClientOptions(nil), func(w http.ResponseWriter, _ *http.Request) {
+			calls.Add(1)
+			w.WriteHeader(http.StatusServiceUnavailable)
+			fmt.Fprint(w, `{"message":"unavailable", "data":{}}`)
+		})
+
+		_, err := kickClient.GetUsers(context.Background(), gokick.NewUserListFilter())
+		require.EqualError(t, err, "Error 503: unavailable")
+		assert.Equal(t, int32(1), calls.Load())
+	})
+
+	t.Run("attempts exhausted", func(t *testing.T) {
+		var calls atomic.Int32
+		kickClient := setupMockClientWithOptions(t, retryClientOptions(fastRetryPolicy()), func(w http.ResponseWriter, _ *http.Request) {
+			calls.Add(1)
+			w.WriteHeader(http.StatusServiceUnavailable)
+			fmt.Fprint(w, `{"message":"unavailable", "data":{}}`)
+		})
+
+		_, err := kickClient.GetUsers(context.Background(), gokick.NewUserListFilter())
+		require.EqualError(t, err, "Error 503: unavailable")
+		assert.Equal(t, int32(3), calls.Load())
+	})
+
+	t.Run("server error on POST is not retried", func(t *testing.T) {
+		var calls atomic.Int32
+		kickClient := setupMockClientWithOptions(t, retryClientOptions(fastRetryPolicy()), func(w http.ResponseWriter, _ *http.Request) {
+			calls.Add(1)
+			w.WriteHeader(http.StatusInternalServerError)
+			fmt.Fprint(w, `{"message":"internal error", "data":{}}`)
+		})
+
+		_, err := kickClient.SendChatMessage(context.Background(), nil, "hello", nil, gokick.MessageTypeUser)
+		require.EqualError(t, err, "Error 500: internal error")
+		assert.Equal(t, int32(1), calls.Load())
+	})
+
+	t.Run("client error is not retried", func(t *testing.T) {
+		var calls atomic.Int32
+		kickClient := setupMockClientWithOptions(t, retryClientOptions(fastRetryPolicy()), func(w http.ResponseWriter, _ *http.Request) {
+			calls.Add(1)
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(w, `{"message":"bad request", "data":{}}`)
+		})
+
+		_, err := kickClient.GetUsers(context.Background(), gokick.NewUserListFilter())
+		require.EqualError(t, err, "Error 400: bad request")
+		assert.Equal(t, int32(1), calls.Load())
+	})
+
+	t.Run("Retry-After longer than MaxDelay", func(t *testing.T) {
+		var calls atomic.Int32
+		kickClient := setupMockClientWithOptions(t, retryClientOptions(fastRetryPolicy()), func(w http.ResponseWriter, _ *http.Request) {
+			calls.Add(1)
+			w.Header().Set("Retry-After", "60")
+			w.WriteHeader(http.StatusTooManyRequests)
+			fmt.Fprint(w, `{"message":"too many requests", "data":{}}`)
+		})
+
+		_, err := kickClient.GetUsers(context.Background(), gokick.NewUserListFilter())
+		require.EqualError(t, err, "Error 429: too many requests")
+		assert.Equal(t, int32(1), calls.Load())
+	})
+
+	t.Run("context cancelled while waiting", func(t *testing.T) {
+		policy := fastRetryPolicy()
+		policy.BaseDelay = time.Hour
+		policy.MaxDelay = time.Hour
+
+		ctx, cancel := context.WithCancel(context.Background())
+		kickClient := setupMockClientWithOptions(t, retryClientOptions(policy), func(w http.ResponseWriter, _ *http.Request) {
+			cancel()
+			w.WriteHeader(http.StatusServiceUnavailable)
+			fmt.Fprint(w, `{"message":"unavailable", "data":{}}`)
+		})
+
+		_, err := kickClient.GetUsers(ctx, gokick.NewUserListFilter())
+		require.ErrorIs(t, err, context.Canceled)
+	})
+
+	t.Run("network error on POST is not retried", func(t *testing.T) {
+		transport := &flakyRoundTripper{failures: 1}
+		kickClient, err := gokick.NewClient(&gokick.ClientOptions{
+			UserAccessToken: "access-token",
+			HTTPClient:      &http.Client{Transport: transport},
+			RetryPolicy:     fastRetryPolicy(),
+		})
+		require.NoError(t, err)
+
+		_, err = kickClient.SendChatMessage(context.Background(), nil, "hello", nil, gokick.MessageTypeUser)
+		require.Error(t, err)
+		assert.Equal(t, 1, transport.calls)
+	})
+}
+
+type flakyRoundTripper struct {
+	failures int
+	calls    int
+}
+
+func (f *flakyRoundTripper) RoundTrip(_ *http.Request) (*http.Response, error) {
+	f.calls++
+	if f.calls <= f.failures {
+		return nil, errors.New("connection reset by peer")
+	}
+
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{},
+		Body:       io.NopCloser(strings.NewReader(`{"data":[]}`)),
+	}, nil
+}
@@ -0,0 +1,37 @@
+package gokick
+
+import "strings"
+
+// routes lists the endpoint paths containing identifiers, so that they can be reported with bounded cardinality.
+var routes = []string{
+	"/public/v1/channels/rewards/{id}",
+	"/public/v1/chat/{message_id}",
+}
+
+// Route returns the route of an endpoint path, with identifiers replaced by placeholders such as
+// "/public/v1/chat/{message_id}". Paths without identifiers are returned unchanged. It is meant to label
+// traces and metrics.
+func Route(path string) string {
+	segments := strings.Split(path, "/")
+
+	for _, route := range routes {
+		routeSegments := strings.Split(route, "/")
+		if len(routeSegments) != len(segments) {
+			continue
+		}
+
+		match := true
+		for i, segment := range routeSegments {
+			if !strings.HasPrefix(segment, "{") && segment != segments[i] {
+				match = false
+				break
+			}
+		}
+
+		if match {
+			return route
+		}
+	}
+
+	return path
+}
@@ -0,0 +1,23 @@
+package gokick_test
+
+import (
+	"testing"
+
+	"github.com/scorfly/gokick"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRoute(t *testing.T) {
+	testCases := map[string]string{
+		"/public/v1/channels/rewards/01JXYZ": "/public/v1/channels/rewards/{id}",
+		"/public/v1/channels/rewards":        "/public/v1/channels/rewards",
+		"/public/v1/chat/message-id":         "/public/v1/chat/{message_id}",
+		"/public/v1/chat":                    "/public/v1/chat",
+		"/public/v1/users":                   "/public/v1/users",
+		"/oauth/token":                       "/oauth/token",
+	}
+
+	for path, expected := range testCases {
+		assert.Equal(t, expected, gokick.Route(path), path)
+	}
+}
@@ -0,0 +1,140 @@
+package gokick
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+const maxCachedTokenScopes = 1024
+
+// ErrMissingScope is matched by errors.Is on a MissingScopeError.
+var ErrMissingScope = errors.New("gokick: missing scope")
+
+// MissingScopeError is returned before sending a request when ClientOptions.ScopePreflight is enabled and the
+// token lacks scopes the endpoint requires.
+type MissingScopeError struct {
+	Method  string
+	Path    string
+	Missing []Scope
+}
+
+func (e *MissingScopeError) Error() string {
+	missing := make([]string, len(e.Missing))
+	for i, scope := range e.Missing {
+		missing[i] = scope.String()
+	}
+
+	return fmt.Sprintf("%s: %s %s requires %s", ErrMissingScope, e.Method, e.Path, strings.Join(missing, ", "))
+}
+
+func (e *MissingScopeError) Is(target error) bool {
+	return target == ErrMissingScope
+}
+
+type endpointScopes struct {
+	method string
+	path   string
+	scopes []Scope
+}
+
+// requiredScopesByEndpoint lists the scopes a user token needs on each endpoint. Paths are matched by prefix,
+// the first match wins.
+var requiredScopesByEndpoint = []endpointScopes{
+	{method: http.MethodGet, path: "/public/v1/channels/rewards", scopes: []Scope{ScopeChannelRewardsRead}},
+	{method: http.MethodPost, path: "/public/v1/channels/rewards", scopes: []Scope{ScopeChannelRewardsWrite}},
+	{method: http.MethodPatch, path: "/public/v1/channels/rewards", scopes: []Scope{ScopeChannelRewardsWrite}},
+	{method: http.MethodDelete, path: "/public/v1/channels/rewards", scopes: []Scope{ScopeChannelRewardsWrite}},
+	{method: http.MethodGet, path: "/public/v1/channels", scopes: []Scope{ScopeChannelRead}},
+	{method: http.MethodPatch, path: "/public/v1/channels", scopes: []Scope{ScopeChannelWrite}},
+	{method: http.MethodPost, path: "/public/v1/chat", scopes: []Scope{ScopeChatWrite}},
+	{method: http.MethodDelete, path: "/public/v1/chat/", scopes: []Scope{ScopeModerationChatMessageManage}},
+	{method: http.MethodGet, path: "/public/v1/events/subscriptions", scopes: []Scope{ScopeEventSubscribe}},
+	{method: http.MethodPost, path: "/public/v1/events/subscriptions", scopes: []Scope{ScopeEventSubscribe}},
+	{method: http.MethodDelete, path: "/public/v1/events/subscriptions", scopes: []Scope{ScopeEventSubscribe}},
+	{method: http.MethodGet, path: "/public/v1/kicks/leaderboard", scopes: []Scope{ScopeKicksRead}},
+	{method: http.MethodPost, path: "/public/v1/moderation/bans", scopes: []Scope{ScopeModerationBan}},
+	{method: http.MethodDelete, path: "/public/v1/moderation/bans", scopes: []Scope{ScopeModerationBan}},
+	{method: http.MethodGet, path: "/public/v1/users", scopes: []Scope{ScopeUserRead}},
+}
+
+// RequiredScopes returns the scopes a user access token needs to call the endpoint, or nil when it needs none.
+func RequiredScopes(method, path string) []Scope {
+	for _, endpoint := range requiredScopesByEndpoint {
+		if endpoint.method == method && strings.HasPrefix(path, endpoint.path) {
+			return endpoint.scopes
+		}
+	}
+
+	return nil
+}
+
+// checkScopes fails with a MissingScopeError when ScopePreflight is enabled and the user token req is sent with
+// lacks scopes required by the endpoint. The scopes of each token are introspected once and cached.
+// When introspection fails, the request is sent anyway and Kick decides.
+func (c *Client) checkScopes(req *http.Request) error {
+	if !c.options.ScopePreflight {
+		return nil
+	}
+
+	required := RequiredScopes(req.Method, req.URL.Path)
+	if len(required) == 0 {
+		return nil
+	}
+
+	c.mu.Lock()
+	kind, token := c.selectToken(req.Context())
+	granted, cached := c.tokenScopes[token]
+	c.mu.Unlock()
+
+	if kind == tokenKindApp || token == "" {
+		return nil
+	}
+
+	if !cached {
+		var ok bool
+		granted, ok = c.introspectScopes(req.Context(), token)
+		if !ok {
+			return nil
+		}
+	}
+
+	missing := granted.Missing(required...)
+	if len(missing) > 0 {
+		return &MissingScopeError{Method: req.Method, Path: req.URL.Path, Missing: missing}
+	}
+
+	return nil
+}
+
+// introspectScopes returns the scopes granted to token, introspecting it once for all concurrent callers.
+func (c *Client) introspectScopes(ctx context.Context, token string) (ScopeSet, bool) {
+	result, err, _ := c.refreshGroup.Do("scopes:"+token, func() (interface{}, error) {
+		response, err := c.TokenIntrospect(WithAccessToken(ctx, token))
+		if err != nil {
+			return nil, err
+		}
+
+		if !response.Result.Active {
+			return nil, fmt.Errorf("token is not active")
+		}
+
+		granted := response.Result.Scopes()
+
+		c.mu.Lock()
+		if c.tokenScopes == nil || len(c.tokenScopes) >= maxCachedTokenScopes {
+			c.tokenScopes = make(map[string]ScopeSet)
+		}
+		c.tokenScopes[token] = granted
+		c.mu.Unlock()
+
+		return granted, nil
+	})
+	if err != nil {
+		return ScopeSet{}, false
+	}
+
+	return result.(ScopeSet), true
+}
@@ -0,0 +1,121 @@
+package gokick_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/scorfly/gokick"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupScopePreflightClient grants scopes to every token except "inactive-access", and counts introspections
+// and API calls.
+func setupScopePreflightClient(t *testing.T, scopes string, introspections, calls *atomic.Int32) *gokick.Client {
+	t.Helper()
+
+	options := gokick.ClientOptions{UserAccessToken: "access-token", ScopePreflight: true}
+
+	return setupMockClientWithOptions(t, options, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/oauth/token/introspect" {
+			introspections.Add(1)
+			active := r.Header.Get("Authorization") != "Bearer inactive-access"
+			fmt.Fprintf(w, `{"data":{"active":%t,"scope":%q,"token_type":"user"}}`, active, scopes)
+			return
+		}
+
+		calls.Add(1)
+		fmt.Fprint(w, `{"data":[]}`)
+	})
+}
+
+func TestRequiredScopes(t *testing.T) {
+	assert.Equal(t, []gokick.Scope{gokick.ScopeModerationBan}, gokick.RequiredScopes(http.MethodPost, "/public/v1/moderation/bans"))
+	assert.Equal(t, []gokick.Scope{gokick.ScopeChannelRewardsWrite}, gokick.RequiredScopes(http.MethodPatch, "/public/v1/channels/rewards/1"))
+	assert.Equal(t, []gokick.Scope{gokick.ScopeChannelWrite}, gokick.RequiredScopes(http.MethodPatch, "/public/v1/channels"))
+	assert.Equal(t, []gokick.Scope{gokick.ScopeModerationChatMessageManage}, gokick.RequiredScopes(http.MethodDelete, "/public/v1/chat/id"))
+	assert.Nil(t, gokick.RequiredScopes(http.MethodGet, "/public/v1/livestreams"))
+}
+
+func TestScopePreflight(t *testing.T) {
+	t.Run("missing scope fails before the request", func(t *testing.T) {
+		var introspections, calls atomic.Int32
+		kickClient := setupScopePreflightClient(t, "user:read chat:write unknown:scope", &introspections, &calls)
+
+		_, err := kickClient.BanUser(context.Background(), 1, 2, nil, nil)
+		require.ErrorIs(t, err, gokick.ErrMissingScope)
+		require.EqualError(t, err, "failed to make request: gokick: missing scope: POST /public/v1/moderation/bans requires moderation:ban")
+
+		var missingScopeErr *gokick.MissingScopeError
+		require.ErrorAs(t, err, &missingScopeErr)
+		assert.Equal(t, []gokick.Scope{gokick.ScopeModerationBan}, missingScopeErr.Missing)
+		assert.Equal(t, int32(0), calls.Load())
+	})
+
+	t.Run("scopes are introspected once", func(t *testing.T) {
+		var introspections, calls atomic.Int32
+		kickClient := setupScopePreflightClient(t, "user:read", &introspections, &calls)
+
+		var wg sync.WaitGroup
+		for range 10 {
+			wg.Go(func() {
+				_, err := kickClient.GetUsers(context.Background(), gokick.NewUserListFilter())
+				assert.NoError(t, err)
+			})
+		}
+		wg.Wait()
+
+		_, err := kickClient.GetKicksLeaderboard(context.Background(), gokick.NewKicksLeaderboardFilter())
+		require.ErrorIs(t, err, gokick.ErrMissingScope)
+
+		assert.Equal(t, int32(1), introspections.Load())
+		assert.Equal(t, int32(10), calls.Load())
+	})
+
+	t.Run("each explicit token is introspected", func(t *testing.T) {
+		var introspections, calls atomic.Int32
+		kickClient := setupScopePreflightClient(t, "user:read", &introspections, &calls)
+
+		for _, token := range []string{"a", "b", "a"} {
+			_, err := kickClient.GetUsers(gokick.WithAccessToken(context.Background(), token), gokick.NewUserListFilter())
+			require.NoError(t, err)
+		}
+		assert.Equal(t, int32(2), introspections.Load())
+	})
+
+	t.Run("endpoints without scope are not checked", func(t *testing.T) {
+		var introspections, calls atomic.Int32
+		kickClient := setupScopePreflightClient(t, "", &introspections, &calls)
+
+		_, err := kickClient.GetLivestreams(context.Background(), gokick.NewLivestreamListFilter())
+		require.NoError(t, err)
+		assert.Equal(t, int32(0), introspections.Load())
+	})
+
+	t.Run("failed introspection lets the request through", func(t *testing.T) {
+		var introspections, calls atomic.Int32
+		kickClient := setupScopePreflightClient(t, "", &introspections, &calls)
+		kickClient.SetUserAccessToken("inactive-access")
+
+		for range 2 {
+			_, err := kickClient.GetUsers(context.Background(), gokick.NewUserListFilter())
+			require.NoError(t, err)
+		}
+		assert.Equal(t, int32(2), introspections.Load())
+		assert.Equal(t, int32(2), calls.Load())
+	})
+
+	t.Run("app token is not checked", func(t *testing.T) {
+		var introspections, calls atomic.Int32
+		kickClient := setupScopePreflightClient(t, "", &introspections, &calls)
+		kickClient.SetAppAccessToken("app-access")
+
+		_, err := kickClient.GetUsers(gokick.WithAppAccessToken(context.Background()), gokick.NewUserListFilter())
+		require.NoError(t, err)
+		assert.Equal(t, int32(0), introspections.Load())
+	})
+}
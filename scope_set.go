@@ -0,0 +1,147 @@
+package gokick
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// ScopeSet is an immutable set of scopes, as granted to a token. Scopes unknown to this version of the library
+// are kept aside so that they survive a round trip through String or JSON.
+type ScopeSet struct {
+	scopes  map[Scope]struct{}
+	unknown []string
+}
+
+func NewScopeSet(scopes ...Scope) ScopeSet {
+	set := ScopeSet{scopes: make(map[Scope]struct{}, len(scopes))}
+	for _, scope := range scopes {
+		set.scopes[scope] = struct{}{}
+	}
+
+	return set
+}
+
+// ParseScopeSet parses a space-separated list of scopes, as returned by Kick in the scope fields.
+func ParseScopeSet(value string) ScopeSet {
+	return newScopeSetFromNames(strings.Fields(value))
+}
+
+func newScopeSetFromNames(names []string) ScopeSet {
+	set := NewScopeSet()
+	for _, name := range names {
+		scope, err := NewScope(name)
+		if err != nil {
+			if !slices.Contains(set.unknown, name) {
+				set.unknown = append(set.unknown, name)
+			}
+			continue
+		}
+		set.scopes[scope] = struct{}{}
+	}
+
+	return set
+}
+
+func (s ScopeSet) Has(scope Scope) bool {
+	_, ok := s.scopes[scope]
+	return ok
+}
+
+func (s ScopeSet) HasAll(scopes ...Scope) bool {
+	return len(s.Missing(scopes...)) == 0
+}
+
+// Missing returns the scopes of scopes not in the set, in the same order.
+func (s ScopeSet) Missing(scopes ...Scope) []Scope {
+	var missing []Scope
+	for _, scope := range scopes {
+		if !s.Has(scope) && !slices.Contains(missing, scope) {
+			missing = append(missing, scope)
+		}
+	}
+
+	return missing
+}
+
+func (s ScopeSet) Union(other ScopeSet) ScopeSet {
+	union := NewScopeSet(append(s.Scopes(), other.Scopes()...)...)
+	for _, name := range append(slices.Clone(s.unknown), other.unknown...) {
+		if !slices.Contains(union.unknown, name) {
+			union.unknown = append(union.unknown, name)
+		}
+	}
+
+	return union
+}
+
+// Scopes returns the known scopes of the set, sorted.
+func (s ScopeSet) Scopes() []Scope {
+	scopes := make([]Scope, 0, len(s.scopes))
+	for scope := range s.scopes {
+		scopes = append(scopes, scope)
+	}
+	slices.Sort(scopes)
+
+	return scopes
+}
+
+// Unknown returns the scopes of the set this version of the library does not know, as received.
+func (s ScopeSet) Unknown() []string {
+	return slices.Clone(s.unknown)
+}
+
+func (s ScopeSet) Len() int {
+	return len(s.scopes) + len(s.unknown)
+}
+
+// names returns every scope of the set as a string, known scopes first.
+func (s ScopeSet) names() []string {
+	names := make([]string, 0, s.Len())
+	for _, scope := range s.Scopes() {
+		names = append(names, scope.String())
+	}
+
+	return append(names, s.unknown...)
+}
+
+// String returns the scopes separated by spaces, as expected by Kick.
+func (s ScopeSet) String() string {
+	return strings.Join(s.names(), " ")
+}
+
+// MarshalJSON encodes the set as an array of scope names.
+func (s ScopeSet) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.names())
+}
+
+// UnmarshalJSON decodes an array of scope names, or a space-separated string as returned by Kick.
+func (s *ScopeSet) UnmarshalJSON(data []byte) error {
+	var names []string
+	err := json.Unmarshal(data, &names)
+	if err == nil {
+		*s = newScopeSetFromNames(names)
+		return nil
+	}
+
+	var value string
+	err = json.Unmarshal(data, &value)
+	if err != nil {
+		return fmt.Errorf("failed to unmarshal scope set: %w", err)
+	}
+
+	*s = ParseScopeSet(value)
+
+	return nil
+}
+
+// Scopes returns the scopes granted to the token.
+func (r TokenResponse) Scopes() ScopeSet {
+	return ParseScopeSet(r.Scope)
+}
+
+// Scopes returns the scopes granted to the token.
+func (r TokenIntrospectResponse) Scopes() ScopeSet {
+	return ParseScopeSet(r.Scope)
+}
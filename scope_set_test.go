@@ -0,0 +1,84 @@
+package gokick_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/scorfly/gokick"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseScopeSet(t *testing.T) {
+	set := gokick.ParseScopeSet("chat:write  user:read future:scope user:read")
+
+	assert.Equal(t, []gokick.Scope{gokick.ScopeUserRead, gokick.ScopeChatWrite}, set.Scopes())
+	assert.Equal(t, []string{"future:scope"}, set.Unknown())
+	assert.Equal(t, 3, set.Len())
+	assert.Equal(t, "user:read chat:write future:scope", set.String())
+
+	assert.Equal(t, 0, gokick.ParseScopeSet("").Len())
+}
+
+func TestScopeSetOperations(t *testing.T) {
+	set := gokick.NewScopeSet(gokick.ScopeUserRead, gokick.ScopeChatWrite)
+
+	assert.True(t, set.Has(gokick.ScopeUserRead))
+	assert.False(t, set.Has(gokick.ScopeModerationBan))
+	assert.True(t, set.HasAll(gokick.ScopeChatWrite, gokick.ScopeUserRead))
+	assert.True(t, set.HasAll())
+	assert.False(t, set.HasAll(gokick.ScopeChatWrite, gokick.ScopeModerationBan))
+	assert.Equal(
+		t,
+		[]gokick.Scope{gokick.ScopeModerationBan, gokick.ScopeKicksRead},
+		set.Missing(gokick.ScopeModerationBan, gokick.ScopeUserRead, gokick.ScopeKicksRead, gokick.ScopeModerationBan),
+	)
+	assert.Nil(t, set.Missing(gokick.ScopeUserRead))
+
+	union := set.Union(gokick.ParseScopeSet("moderation:ban other:scope"))
+	assert.Equal(t, "user:read chat:write moderation:ban other:scope", union.String())
+	assert.Equal(t, "user:read chat:write", set.String())
+
+	var zero gokick.ScopeSet
+	assert.False(t, zero.Has(gokick.ScopeUserRead))
+	assert.Equal(t, "user:read", zero.Union(gokick.NewScopeSet(gokick.ScopeUserRead)).String())
+}
+
+func TestScopeSetJSON(t *testing.T) {
+	t.Run("marshal", func(t *testing.T) {
+		content, err := json.Marshal(struct {
+			Granted gokick.ScopeSet `json:"granted"`
+		}{Granted: gokick.ParseScopeSet("chat:write other:scope user:read")})
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"granted":["user:read","chat:write","other:scope"]}`, string(content))
+
+		content, err = json.Marshal(gokick.ScopeSet{})
+		require.NoError(t, err)
+		assert.JSONEq(t, `[]`, string(content))
+	})
+
+	t.Run("unmarshal", func(t *testing.T) {
+		testCases := map[string]string{
+			"array":  `["chat:write","user:read","other:scope"]`,
+			"string": `"chat:write user:read other:scope"`,
+		}
+
+		for name, content := range testCases {
+			t.Run(name, func(t *testing.T) {
+				var set gokick.ScopeSet
+				require.NoError(t, json.Unmarshal([]byte(content), &set))
+				assert.Equal(t, "user:read chat:write other:scope", set.String())
+			})
+		}
+	})
+
+	t.Run("unmarshal error", func(t *testing.T) {
+		var set gokick.ScopeSet
+		require.ErrorContains(t, json.Unmarshal([]byte(`42`), &set), "failed to unmarshal scope set")
+	})
+}
+
+func TestResponseScopes(t *testing.T) {
+	assert.True(t, gokick.TokenResponse{Scope: "user:read chat:write"}.Scopes().HasAll(gokick.ScopeUserRead, gokick.ScopeChatWrite))
+	assert.True(t, gokick.TokenIntrospectResponse{Scope: "kicks:read"}.Scopes().Has(gokick.ScopeKicksRead))
+}
@@ -1,172 +1,69 @@
 package main
 
-// This script is a copy/paste from a JS script written by [ACPixel](https://github.com/ACPixel)
+// This script was originally a copy/paste from a JS script written by [ACPixel](https://github.com/ACPixel)
 // Source: https://gist.github.com/ACPixel/bd71dc716126153e04e41700e8a8820e
+// It now relies on gokick.OAuthFlow for the PKCE verifier, the state protection and the callback server.
 
 import (
-	"crypto/rand"
-	"crypto/sha256"
-	"encoding/base64"
+	"context"
 	"encoding/json"
 	"fmt"
-	"io"
-	"net/http"
-	"net/url"
 	"os"
-	"strings"
+	"os/signal"
+
+	"github.com/scorfly/gokick"
 )
 
 // Put your scopes here.
-var kickScopes = []string{
-	"user:read",
-	"chat:write",
-	"channel:read",
-	"channel:write",
-	"streamkey:read",
-	"events:subscribe",
-	"moderation:ban",
-}
-
-var ENDPOINT = struct {
-	AuthURL  string
-	TokenURL string
-}{
-	AuthURL:  "https://id.kick.com/oauth/authorize",
-	TokenURL: "https://id.kick.com/oauth/token",
+var kickScopes = []gokick.Scope{
+	gokick.ScopeUserRead,
+	gokick.ScopeChatWrite,
+	gokick.ScopeChannelRead,
+	gokick.ScopeChannelWrite,
+	gokick.ScopeStreamkeyRead,
+	gokick.ScopeEventSubscribe,
+	gokick.ScopeModerationBan,
 }
 
 // Put your redirect URL here.
 const redirectURL = "http://localhost:3000/oauth/kick/callback"
 
-// Client ID and Secret from environment variables.
-var (
-	kickClientID     = os.Getenv("KICK_CLIENT_ID")
-	kickClientSecret = os.Getenv("KICK_CLIENT_SECRET")
-)
-
-// PKCE Helper Functions.
-// Generate a random code verifier.
-func generateCodeVerifier() (string, error) {
-	buffer := make([]byte, 32)
-	_, err := rand.Read(buffer)
-	if err != nil {
-		return "", err
-	}
-	return base64.URLEncoding.EncodeToString(buffer), nil
-}
-
-// Generate a code challenge (SHA-256 hash of the verifier).
-func generateCodeChallenge(verifier string) string {
-	hash := sha256.Sum256([]byte(verifier))
-	return base64.URLEncoding.EncodeToString(hash[:])
-}
-
-// Step 1: Generate a verifier, challenge, and send the user to the auth page.
-func oauthKickHandler(w http.ResponseWriter, r *http.Request) {
-	codeVerifier, err := generateCodeVerifier()
-	if err != nil {
-		http.Error(w, "Failed to generate code verifier", http.StatusInternalServerError)
-		return
-	}
-
-	codeChallenge := generateCodeChallenge(codeVerifier)
-
-	/* IMPORTANT:
-	   This is a VERY BAD way to store the verifier. The original non-hashed verifier is needed,
-	   later on when swapping the code for a token.
-	   In a real application you should either store the original verifier in your own
-	   database, or encrypt it with a secret before including it in the state.
-	   It's not inharently wrong to store it in the state param, but if you do,
-	   make sure it is encrypted with a secret and not just base64 encoded.
-	   The verifier system is used to "prove" that the request for authorization was
-	   started by your application, and later that the code exchange was also by your application.
-	*/
-
-	// Store the verifier in the state (not recommended for production).
-	state := base64.StdEncoding.EncodeToString([]byte(fmt.Sprintf(`{"codeVerifier":"%s"}`, codeVerifier)))
-
-	// Build the authorization URL.
-	authParams := url.Values{
-		"client_id":             {kickClientID},
-		"redirect_uri":          {redirectURL},
-		"response_type":         {"code"},
-		"scope":                 {strings.Join(kickScopes, " ")},
-		"state":                 {state},
-		"code_challenge":        {codeChallenge},
-		"code_challenge_method": {"S256"},
-	}
-
-	authURL := fmt.Sprintf("%s?%s", ENDPOINT.AuthURL, authParams.Encode())
-	http.Redirect(w, r, authURL, http.StatusFound)
-}
-
-// Step 2: Handle the redirect from the auth page.
-func oauthKickCallbackHandler(w http.ResponseWriter, r *http.Request) {
-	code := r.URL.Query().Get("code")
-	state := r.URL.Query().Get("state")
-
-	if code == "" {
-		http.Error(w, "Missing authorization code", http.StatusBadRequest)
-		return
-	}
-
-	// Decode the state to get the code verifier.
-	stateBytes, err := base64.StdEncoding.DecodeString(state)
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	// Client ID and Secret from environment variables.
+	client, err := gokick.NewClient(&gokick.ClientOptions{
+		ClientID:     os.Getenv("KICK_CLIENT_ID"),
+		ClientSecret: os.Getenv("KICK_CLIENT_SECRET"),
+	})
 	if err != nil {
-		http.Error(w, "Invalid state", http.StatusBadRequest)
+		fmt.Printf("Failed to create client: %s\n", err.Error())
 		return
 	}
 
-	var stateData struct {
-		CodeVerifier string `json:"codeVerifier"`
-	}
-
-	err = json.Unmarshal(stateBytes, &stateData)
+	flow, err := gokick.NewOAuthFlow(client, gokick.OAuthFlowOptions{
+		RedirectURI: redirectURL,
+		Scopes:      kickScopes,
+	})
 	if err != nil {
-		http.Error(w, "Invalid state data", http.StatusBadRequest)
+		fmt.Printf("Failed to create OAuth flow: %s\n", err.Error())
 		return
 	}
 
-	// Prepare the token request.
-	tokenParams := url.Values{
-		"grant_type":    {"authorization_code"},
-		"client_id":     {kickClientID},
-		"client_secret": {kickClientSecret},
-		"code":          {code},
-		"redirect_uri":  {redirectURL},
-		"code_verifier": {stateData.CodeVerifier},
-	}
-
-	// Send the token request.
-	resp, err := http.PostForm(ENDPOINT.TokenURL, tokenParams)
+	token, err := flow.AuthorizeWithLocalServer(ctx, func(authorizeURL string) {
+		fmt.Printf("Open this URL in your browser:\n\n%s\n\n", authorizeURL)
+	})
 	if err != nil {
-		http.Error(w, fmt.Sprintf("Failed to request token: %s", err.Error()), http.StatusInternalServerError)
+		fmt.Printf("Failed to get user access token: %s\n", err.Error())
 		return
 	}
-	defer resp.Body.Close()
 
-	// Read and return the token response.
-	body, err := io.ReadAll(resp.Body)
+	output, err := json.MarshalIndent(token, "", "  ")
 	if err != nil {
-		http.Error(w, "Failed to read token response", http.StatusInternalServerError)
+		fmt.Printf("Failed to marshal token: %s\n", err.Error())
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	_, err = w.Write(body)
-	if err != nil {
-		http.Error(w, "Failed to write token response", http.StatusInternalServerError)
-	}
-}
-
-func main() {
-	http.HandleFunc("/oauth/kick/", oauthKickHandler)
-	http.HandleFunc("/oauth/kick/callback", oauthKickCallbackHandler)
-
-	fmt.Println("Server running on http://localhost:3000")
-	err := http.ListenAndServe(":3000", nil)
-	if err != nil {
-		fmt.Printf("Server failed to start: %s\n", err.Error())
-		return
-	}
+	fmt.Println(string(output))
 }
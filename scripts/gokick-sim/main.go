@@ -0,0 +1,278 @@
+// Command gokick-sim sends realistic webhook events, signed with a local key pair, to a webhook endpoint. It is meant
+// to test and load-test webhook handlers without Kick.
+//
+//	go run ./scripts/gokick-sim -url http://localhost:8080/webhook -event chat.message.sent -count 100 -rate 10
+//	go run ./scripts/gokick-sim -url http://localhost:8080/webhook -scenario raid
+//
+// The endpoint must verify the events with the public key of the simulator, written next to its private key.
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/scorfly/gokick"
+)
+
+type options struct {
+	url             string
+	events          string
+	count           int
+	rate            float64
+	scenario        string
+	concurrency     int
+	keyPath         string
+	seed            uint64
+	broadcasterID   int
+	broadcasterName string
+	verbose         bool
+}
+
+func main() {
+	var opts options
+	flag.StringVar(&opts.url, "url", "", "webhook endpoint URL (required)")
+	flag.StringVar(&opts.events, "event", "all", `comma-separated subscription names to send, or "all"`)
+	flag.IntVar(&opts.count, "count", 10, "number of events to send, without -scenario")
+	flag.Float64Var(&opts.rate, "rate", 0, "events per second, without -scenario (0 sends as fast as possible)")
+	flag.StringVar(&opts.scenario, "scenario", "",
+		fmt.Sprintf("built-in scenario (%s) or JSON scenario file", strings.Join(scenarioNames(), ", ")))
+	flag.IntVar(&opts.concurrency, "concurrency", 10, "maximum number of requests in flight")
+	flag.StringVar(&opts.keyPath, "key", "gokick-sim.pem", "PEM private key signing the events, generated when missing")
+	flag.Uint64Var(&opts.seed, "seed", 0, "seed of the random fields (0 picks a random seed)")
+	flag.IntVar(&opts.broadcasterID, "broadcaster-id", 1, "user ID of the broadcaster of the events")
+	flag.StringVar(&opts.broadcasterName, "broadcaster", "broadcaster", "username of the broadcaster of the events")
+	flag.BoolVar(&opts.verbose, "v", false, "print every request")
+	flag.Parse()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	err := run(ctx, opts)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to simulate events: %s\n", err.Error())
+		os.Exit(1)
+	}
+}
+
+func run(ctx context.Context, opts options) error {
+	if opts.url == "" {
+		return errors.New("-url is required")
+	}
+
+	loaded, err := buildScenario(opts)
+	if err != nil {
+		return err
+	}
+
+	plan, err := loaded.plan()
+	if err != nil {
+		return err
+	}
+
+	signer, err := loadSigner(opts.keyPath)
+	if err != nil {
+		return err
+	}
+
+	if opts.seed == 0 {
+		opts.seed = randomSeed()
+	}
+	fmt.Printf("Sending events to %s (seed %d)\n", opts.url, opts.seed)
+
+	sim := &simulator{
+		url:     opts.url,
+		signer:  signer,
+		sampler: newSampler(opts.seed, opts.broadcasterID, opts.broadcasterName),
+		client:  &http.Client{Timeout: 10 * time.Second},
+		slots:   make(chan struct{}, max(opts.concurrency, 1)),
+		verbose: opts.verbose,
+		stats:   newStats(),
+	}
+
+	start := time.Now()
+	for i, st := range plan {
+		if len(plan) > 1 {
+			fmt.Printf("Step %d/%d: %d events\n", i+1, len(plan), st.count)
+		}
+
+		err = sim.runStep(ctx, st)
+		if err != nil {
+			break
+		}
+	}
+	sim.wait()
+
+	sim.stats.print(time.Since(start))
+
+	return err
+}
+
+// buildScenario returns the scenario of opts.scenario, or a single step sending opts.count events at opts.rate.
+func buildScenario(opts options) (scenario, error) {
+	if opts.scenario != "" {
+		return loadScenario(opts.scenario)
+	}
+
+	var duration string
+	if opts.rate > 0 {
+		duration = time.Duration(float64(opts.count) / opts.rate * float64(time.Second)).String()
+	}
+
+	return scenario{{Events: strings.Split(opts.events, ","), Count: opts.count, Duration: duration}}, nil
+}
+
+// loadSigner reads the private key of path, or generates it and writes it to path when the file does not exist.
+// The matching public key is written to path with a ".pub" suffix.
+func loadSigner(path string) (*gokick.WebhookSigner, error) {
+	content, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return generateSigner(path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read private key: %w", err)
+	}
+
+	block, _ := pem.Decode(content)
+	if block == nil {
+		return nil, fmt.Errorf("failed to decode private key %s", path)
+	}
+
+	var privateKey interface{}
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		privateKey, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	default:
+		privateKey, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse private key: %w", err)
+	}
+
+	rsaKey, ok := privateKey.(*rsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("private key %s is not an RSA key", path)
+	}
+
+	fmt.Printf("Signing events with %s, verify them with %s.pub\n", path, path)
+
+	return gokick.NewWebhookSigner(rsaKey), nil
+}
+
+func generateSigner(path string) (*gokick.WebhookSigner, error) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate private key: %w", err)
+	}
+
+	der, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal private key: %w", err)
+	}
+
+	err = os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to write private key: %w", err)
+	}
+
+	signer := gokick.NewWebhookSigner(privateKey)
+
+	publicKey, err := signer.PublicKeyPEM()
+	if err != nil {
+		return nil, err
+	}
+
+	err = os.WriteFile(path+".pub", publicKey, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to write public key: %w", err)
+	}
+
+	fmt.Printf("Generated %s, verify the events with %s.pub:\n\n%s\n", path, path, publicKey)
+
+	return signer, nil
+}
+
+type simulator struct {
+	url     string
+	signer  *gokick.WebhookSigner
+	sampler *sampler
+	client  *http.Client
+	slots   chan struct{}
+	wg      sync.WaitGroup
+	verbose bool
+	stats   *stats
+}
+
+// runStep sends the events of st, one per interval, waiting for a free slot before each request.
+func (s *simulator) runStep(ctx context.Context, st plannedStep) error {
+	ticker := time.NewTicker(max(st.interval, time.Nanosecond))
+	defer ticker.Stop()
+
+	for i := range st.count {
+		if i > 0 && st.interval > 0 {
+			select {
+			case <-ticker.C:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+
+		select {
+		case s.slots <- struct{}{}:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+
+		subscriptionName := pick(s.sampler.rand, st.events)
+		request, err := s.signer.NewRequest(ctx, s.url, s.sampler.sample(subscriptionName))
+		if err != nil {
+			<-s.slots
+			return err
+		}
+
+		s.wg.Go(func() {
+			defer func() { <-s.slots }()
+			s.send(request, subscriptionName)
+		})
+	}
+
+	return nil
+}
+
+func (s *simulator) send(request *http.Request, subscriptionName gokick.SubscriptionName) {
+	start := time.Now()
+	response, err := s.client.Do(request)
+	elapsed := time.Since(start)
+
+	if err != nil {
+		s.stats.record(subscriptionName, 0, elapsed)
+		if s.verbose {
+			fmt.Printf("%s: %s\n", subscriptionName, err.Error())
+		}
+		return
+	}
+	_, _ = io.Copy(io.Discard, response.Body)
+	_ = response.Body.Close()
+
+	s.stats.record(subscriptionName, response.StatusCode, elapsed)
+	if s.verbose {
+		fmt.Printf("%s %s: %d in %s\n", subscriptionName, request.Header.Get("Kick-Event-Message-Id"), response.StatusCode,
+			elapsed.Round(time.Millisecond))
+	}
+}
+
+func (s *simulator) wait() {
+	s.wg.Wait()
+}
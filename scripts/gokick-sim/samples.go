@@ -0,0 +1,299 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand/v2"
+	"strings"
+	"time"
+
+	"github.com/scorfly/gokick"
+)
+
+var (
+	usernames = []string{
+		"StreamFan", "NightOwl", "PixelPirate", "LuckyLuke", "SpeedyGonzo", "CasualCarl", "MidnightMia", "RageQuitter",
+		"ChillVibes", "GGnoRE", "ToxicTurtle", "PogChampion", "ClutchKing", "LurkerLarry", "HypeTrain", "SlotSlayer",
+	}
+	chatPhrases = []string{
+		"let's gooo", "that was insane", "first time here", "W stream", "no way", "gg", "chat is this real",
+		"what's the song?", "hello from France", "clip it", "L take", "he's cooking", "can we get a hype",
+	}
+	emotes = []struct {
+		id   string
+		name string
+	}{
+		{"39261", "kkHuh"}, {"39265", "EDMusiC"}, {"37226", "KEKW"}, {"37227", "LULW"}, {"37230", "POLICE"}, {"39272", "ThisIsFine"},
+	}
+	kicksGifts = []struct {
+		amount int
+		name   string
+		tier   string
+	}{
+		{1, "Hell Yeah", "BASIC"}, {10, "Hype", "BASIC"}, {50, "Rage Quit", "MID"}, {100, "Full Send", "MID"},
+		{500, "Flex", "PREMIUM"}, {1000, "Rocket", "PREMIUM"},
+	}
+	rewards = []struct {
+		title     string
+		cost      int
+		userInput bool
+	}{
+		{"Hydrate!", 100, false}, {"Song request", 500, true}, {"Pick the next game", 5000, true}, {"Do 10 push-ups", 1000, false},
+	}
+	redemptionStatuses = []string{"pending", "accepted", "rejected"}
+	giftBurstSizes     = []int{1, 1, 1, 5, 5, 10, 20, 50}
+	categories         = []string{"Just Chatting", "Counter-Strike 2", "Slots & Casino", "Grand Theft Auto V", "IRL"}
+)
+
+// sampler generates realistic events with randomised fields. It is not safe for concurrent use.
+type sampler struct {
+	rand        *rand.Rand
+	broadcaster gokick.UserEvent
+	// recent holds the last chat messages, for replies.
+	recent []*gokick.ChatMessageEvent
+	nextID int
+}
+
+func newSampler(seed uint64, broadcasterUserID int, broadcasterName string) *sampler {
+	return &sampler{
+		rand: rand.New(rand.NewPCG(seed, seed)),
+		broadcaster: gokick.UserEvent{
+			UserID:         broadcasterUserID,
+			Username:       broadcasterName,
+			IsVerified:     true,
+			ProfilePicture: fmt.Sprintf("https://files.kick.com/images/user/%d/profile_image/default.webp", broadcasterUserID),
+			ChannelSlug:    strings.ToLower(broadcasterName),
+		},
+	}
+}
+
+func (s *sampler) sample(subscriptionName gokick.SubscriptionName) interface{} {
+	switch subscriptionName {
+	case gokick.SubscriptionNameChatMessage:
+		return s.chatMessage()
+	case gokick.SubscriptionNameChannelFollow:
+		return &gokick.ChannelFollowEvent{Broadcaster: s.broadcaster, Follower: s.user()}
+	case gokick.SubscriptionNameChannelSubscriptionRenewal:
+		return &gokick.ChannelSubscriptionRenewalEvent{
+			Broadcaster: s.broadcaster,
+			Subscriber:  s.user(),
+			Duration:    2 + s.rand.IntN(24),
+			CreatedAt:   now(),
+			ExpiresAt:   later(30 * 24 * time.Hour),
+		}
+	case gokick.SubscriptionNameChannelSubscriptionGifts:
+		return s.subscriptionGifts()
+	case gokick.SubscriptionNameChannelSubscriptionCreated:
+		return &gokick.ChannelSubscriptionCreatedEvent{
+			Broadcaster: s.broadcaster,
+			Subscriber:  s.user(),
+			Duration:    1,
+			CreatedAt:   now(),
+			ExpiresAt:   later(30 * 24 * time.Hour),
+		}
+	case gokick.SubscriptionNameLivestreamStatusUpdated:
+		return s.livestreamStatus(s.rand.IntN(2) == 0)
+	case gokick.SubscriptionNameLivestreamMetadataUpdated:
+		return s.livestreamMetadata()
+	case gokick.SubscriptionNameModerationBanned:
+		return s.ban()
+	case gokick.SubscriptionNameKicksGifted:
+		return s.kicksGift()
+	case gokick.SubscriptionNameChannelRewardRedemptionUpdated:
+		return s.rewardRedemption()
+	default:
+		return nil
+	}
+}
+
+func (s *sampler) user() gokick.UserEvent {
+	id := 100000 + s.rand.IntN(900000)
+	name := fmt.Sprintf("%s%d", pick(s.rand, usernames), id%1000)
+
+	return gokick.UserEvent{
+		UserID:         id,
+		Username:       name,
+		IsVerified:     s.rand.IntN(20) == 0,
+		ProfilePicture: fmt.Sprintf("https://files.kick.com/images/user/%d/profile_image/default.webp", id),
+		ChannelSlug:    strings.ToLower(name),
+		Identity: &gokick.IdentityEvent{
+			UsernameColor: fmt.Sprintf("#%06X", s.rand.IntN(0x1000000)),
+			Badges:        s.badges(),
+		},
+	}
+}
+
+func (s *sampler) badges() []gokick.Badge {
+	badges := []gokick.Badge{}
+	if s.rand.IntN(3) == 0 {
+		months := 1 + s.rand.IntN(24)
+		badges = append(badges, gokick.Badge{Text: fmt.Sprintf("Subscriber (%d months)", months), Type: "subscriber", Count: months})
+	}
+	if s.rand.IntN(10) == 0 {
+		badges = append(badges, gokick.Badge{Text: "Moderator", Type: "moderator"})
+	}
+
+	return badges
+}
+
+// chatMessage returns a message with emotes, replying to a recent message a third of the time.
+func (s *sampler) chatMessage() *gokick.ChatMessageEvent {
+	s.nextID++
+
+	message := &gokick.ChatMessageEvent{
+		MessageID:   fmt.Sprintf("sim-%08x-%d", s.rand.Uint32(), s.nextID),
+		Broadcaster: s.broadcaster,
+		Sender:      s.user(),
+		CreatedAt:   now(),
+	}
+
+	var content strings.Builder
+	content.WriteString(pick(s.rand, chatPhrases))
+	for range s.rand.IntN(3) {
+		emote := pick(s.rand, emotes)
+		content.WriteString(" ")
+		start := content.Len()
+		fmt.Fprintf(&content, "[emote:%s:%s]", emote.id, emote.name)
+
+		message.Emotes = append(message.Emotes, gokick.ChatMessageEmotesEvent{
+			EmoteID: json.Number(emote.id),
+			Positions: []struct {
+				Start int `json:"s"`
+				End   int `json:"e"`
+			}{{Start: start, End: content.Len() - 1}},
+		})
+	}
+	message.Content = content.String()
+
+	if len(s.recent) > 0 && s.rand.IntN(3) == 0 {
+		replied := pick(s.rand, s.recent)
+		message.RepliesTo.MessageID = replied.MessageID
+		message.RepliesTo.Sender = replied.Sender
+		message.RepliesTo.Content = replied.Content
+		message.Content = "@" + replied.Sender.Username + " " + message.Content
+	}
+
+	s.recent = append(s.recent, message)
+	if len(s.recent) > 20 {
+		s.recent = s.recent[1:]
+	}
+
+	return message
+}
+
+func (s *sampler) subscriptionGifts() *gokick.ChannelSubscriptionGiftsEvent {
+	giftees := make([]gokick.UserEvent, pick(s.rand, giftBurstSizes))
+	for i := range giftees {
+		giftees[i] = s.user()
+	}
+
+	gifter := s.user()
+	gifter.IsAnonymous = s.rand.IntN(10) == 0
+
+	return &gokick.ChannelSubscriptionGiftsEvent{
+		Broadcaster: s.broadcaster,
+		Gifter:      gifter,
+		Giftees:     giftees,
+		CreatedAt:   now(),
+		ExpiresAt:   later(30 * 24 * time.Hour),
+	}
+}
+
+func (s *sampler) livestreamStatus(isLive bool) *gokick.LivestreamStatusUpdatedEvent {
+	event := &gokick.LivestreamStatusUpdatedEvent{
+		Broadcaster: s.broadcaster,
+		IsLive:      isLive,
+		Title:       fmt.Sprintf("%s with chat !socials", pick(s.rand, categories)),
+		StartedAt:   now(),
+	}
+	if !isLive {
+		event.StartedAt = later(-time.Duration(1+s.rand.IntN(6)) * time.Hour)
+		event.EndedAt = now()
+	}
+
+	return event
+}
+
+func (s *sampler) livestreamMetadata() *gokick.LivestreamMetadataUpdatedEvent {
+	event := &gokick.LivestreamMetadataUpdatedEvent{Broadcaster: s.broadcaster}
+	event.Metadata.Title = fmt.Sprintf("%s with chat !socials", pick(s.rand, categories))
+	event.Metadata.Language = "en"
+	event.Metadata.HasMatureContent = s.rand.IntN(5) == 0
+	event.Metadata.Category.ID = 1 + s.rand.IntN(len(categories))
+	event.Metadata.Category.Name = categories[event.Metadata.Category.ID-1]
+	event.Metadata.Category.Thumbnail = fmt.Sprintf("https://files.kick.com/images/subcategories/%d/banner/default.webp",
+		event.Metadata.Category.ID)
+
+	return event
+}
+
+func (s *sampler) ban() *gokick.ModerationBannedEvent {
+	event := &gokick.ModerationBannedEvent{
+		Broadcaster: s.broadcaster,
+		Moderator:   s.user(),
+		BannedUser:  s.user(),
+	}
+	event.Metadata.Reason = pick(s.rand, []string{"spam", "harassment", "ban evasion", ""})
+	event.Metadata.CreatedAt = now()
+	if s.rand.IntN(2) == 0 {
+		event.Metadata.ExpiresAt = later(time.Duration(1+s.rand.IntN(60)) * time.Minute)
+	}
+
+	return event
+}
+
+func (s *sampler) kicksGift() *gokick.KicksGiftedEvent {
+	gift := pick(s.rand, kicksGifts)
+
+	event := &gokick.KicksGiftedEvent{
+		Broadcaster: s.broadcaster,
+		Sender:      s.user(),
+		CreatedAt:   now(),
+	}
+	event.Gift.Amount = gift.amount
+	event.Gift.Name = gift.name
+	event.Gift.Type = gift.tier
+	event.Gift.Tier = gift.tier
+	event.Gift.Message = pick(s.rand, []string{"", "w", "love the stream", "for the hydration"})
+	event.Gift.PinnedTimeSeconds = gift.amount / 10 * 60
+
+	return event
+}
+
+func (s *sampler) rewardRedemption() *gokick.ChannelRewardRedemptionUpdatedEvent {
+	reward := pick(s.rand, rewards)
+
+	event := &gokick.ChannelRewardRedemptionUpdatedEvent{
+		ID:          fmt.Sprintf("sim-redemption-%08x", s.rand.Uint32()),
+		Status:      pick(s.rand, redemptionStatuses),
+		RedeemedAt:  now(),
+		Redeemer:    s.user(),
+		Broadcaster: s.broadcaster,
+	}
+	if reward.userInput {
+		event.UserInput = pick(s.rand, chatPhrases)
+	}
+	event.Reward.ID = fmt.Sprintf("sim-reward-%s", strings.ToLower(strings.ReplaceAll(reward.title, " ", "-")))
+	event.Reward.Title = reward.title
+	event.Reward.Cost = reward.cost
+	event.Reward.Description = "Simulated reward"
+
+	return event
+}
+
+// randomSeed returns a seed for the random fields.
+func randomSeed() uint64 {
+	return rand.Uint64()
+}
+
+func pick[T any](r *rand.Rand, values []T) T {
+	return values[r.IntN(len(values))]
+}
+
+func now() string {
+	return time.Now().UTC().Format(time.RFC3339)
+}
+
+func later(delay time.Duration) string {
+	return time.Now().Add(delay).UTC().Format(time.RFC3339)
+}
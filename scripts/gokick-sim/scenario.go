@@ -0,0 +1,156 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/scorfly/gokick"
+)
+
+// step sends Count events spread evenly over Duration, each of a random name among Events.
+type step struct {
+	Events   []string `json:"events"`
+	Count    int      `json:"count"`
+	Duration string   `json:"duration"`
+}
+
+// scenario is a list of steps run one after the other.
+type scenario []step
+
+var scenarios = map[string]scenario{
+	"raid": {
+		{Events: []string{"livestream.status.updated"}, Count: 1},
+		{Events: []string{"channel.followed"}, Count: 200, Duration: "10s"},
+		{Events: []string{"chat.message.sent"}, Count: 300, Duration: "15s"},
+	},
+	"gift-burst": {
+		{Events: []string{"channel.subscription.gifts"}, Count: 20, Duration: "3s"},
+		{Events: []string{"chat.message.sent", "kicks.gifted"}, Count: 100, Duration: "10s"},
+	},
+	"chat-storm": {
+		{Events: []string{"chat.message.sent"}, Count: 1500, Duration: "30s"},
+	},
+	"stream": {
+		{Events: []string{"livestream.status.updated"}, Count: 1},
+		{Events: []string{"livestream.metadata.updated"}, Count: 1},
+		{
+			Events: []string{
+				"chat.message.sent", "chat.message.sent", "chat.message.sent", "chat.message.sent", "channel.followed",
+				"channel.subscription.new", "channel.subscription.renewal", "kicks.gifted", "channel.reward.redemption.updated",
+			},
+			Count:    120,
+			Duration: "60s",
+		},
+		{Events: []string{"channel.subscription.gifts", "moderation.banned"}, Count: 5, Duration: "5s"},
+	},
+}
+
+// scenarioNames returns the names of the built-in scenarios, sorted.
+func scenarioNames() []string {
+	names := make([]string, 0, len(scenarios))
+	for name := range scenarios {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	return names
+}
+
+// loadScenario returns the built-in scenario name, or the scenario of the JSON file name.
+func loadScenario(name string) (scenario, error) {
+	if builtin, ok := scenarios[name]; ok {
+		return builtin, nil
+	}
+
+	content, err := os.ReadFile(name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read scenario (built-in ones are %s): %w", strings.Join(scenarioNames(), ", "), err)
+	}
+
+	var loaded scenario
+	err = json.Unmarshal(content, &loaded)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse scenario: %w", err)
+	}
+
+	return loaded, nil
+}
+
+// plannedStep is a step with its events parsed.
+type plannedStep struct {
+	events   []gokick.SubscriptionName
+	count    int
+	interval time.Duration
+}
+
+func (s scenario) plan() ([]plannedStep, error) {
+	if len(s) == 0 {
+		return nil, errors.New("scenario has no steps")
+	}
+
+	planned := make([]plannedStep, 0, len(s))
+	for i, st := range s {
+		events, err := parseEvents(st.Events)
+		if err != nil {
+			return nil, fmt.Errorf("step %d: %w", i+1, err)
+		}
+
+		if st.Count <= 0 {
+			return nil, fmt.Errorf("step %d: count must be positive", i+1)
+		}
+
+		var duration time.Duration
+		if st.Duration != "" {
+			duration, err = time.ParseDuration(st.Duration)
+			if err != nil {
+				return nil, fmt.Errorf("step %d: %w", i+1, err)
+			}
+		}
+
+		planned = append(planned, plannedStep{
+			events:   events,
+			count:    st.Count,
+			interval: duration / time.Duration(st.Count),
+		})
+	}
+
+	return planned, nil
+}
+
+// parseEvents parses subscription names, "all" standing for every one of them.
+func parseEvents(names []string) ([]gokick.SubscriptionName, error) {
+	if len(names) == 0 {
+		return nil, errors.New("no events")
+	}
+
+	var events []gokick.SubscriptionName
+	for _, name := range names {
+		if name == "all" {
+			events = append(events, allSubscriptionNames()...)
+			continue
+		}
+
+		subscriptionName, err := gokick.NewSubscriptionName(strings.TrimSpace(name))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse event: %w", err)
+		}
+		events = append(events, subscriptionName)
+	}
+
+	return events, nil
+}
+
+// allSubscriptionNames returns every subscription name known to gokick, walking the enum until its String is unknown.
+func allSubscriptionNames() []gokick.SubscriptionName {
+	var names []gokick.SubscriptionName
+	for name := gokick.SubscriptionName(0); name.String() != "unknown"; name++ {
+		names = append(names, name)
+	}
+
+	return names
+}
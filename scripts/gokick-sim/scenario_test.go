@@ -0,0 +1,111 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/scorfly/gokick"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScenarioPlan(t *testing.T) {
+	t.Run("built-in scenarios", func(t *testing.T) {
+		for _, name := range scenarioNames() {
+			loaded, err := loadScenario(name)
+			require.NoError(t, err)
+
+			_, err = loaded.plan()
+			require.NoError(t, err, name)
+		}
+	})
+
+	t.Run("interval", func(t *testing.T) {
+		planned, err := scenario{
+			{Events: []string{"chat.message.sent", " kicks.gifted "}, Count: 10, Duration: "5s"},
+			{Events: []string{"channel.followed"}, Count: 1},
+		}.plan()
+		require.NoError(t, err)
+
+		assert.Equal(t, []plannedStep{
+			{
+				events:   []gokick.SubscriptionName{gokick.SubscriptionNameChatMessage, gokick.SubscriptionNameKicksGifted},
+				count:    10,
+				interval: 500 * time.Millisecond,
+			},
+			{events: []gokick.SubscriptionName{gokick.SubscriptionNameChannelFollow}, count: 1},
+		}, planned)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		testCases := map[string]struct {
+			scenario scenario
+			err      string
+		}{
+			"no steps":  {scenario: scenario{}, err: "scenario has no steps"},
+			"no events": {scenario: scenario{{Count: 1}}, err: "step 1: no events"},
+			"unknown event": {
+				scenario: scenario{{Events: []string{"stream.started"}, Count: 1}},
+				err:      "step 1: failed to parse event: unknown name: stream.started",
+			},
+			"count": {
+				scenario: scenario{{Events: []string{"all"}, Count: 1}, {Events: []string{"all"}}},
+				err:      "step 2: count must be positive",
+			},
+			"invalid duration": {
+				scenario: scenario{{Events: []string{"all"}, Count: 1, Duration: "soon"}},
+				err:      `step 1: time: invalid duration "soon"`,
+			},
+		}
+
+		for name, tc := range testCases {
+			t.Run(name, func(t *testing.T) {
+				_, err := tc.scenario.plan()
+				require.EqualError(t, err, tc.err)
+			})
+		}
+	})
+}
+
+func TestParseEventsAll(t *testing.T) {
+	events, err := parseEvents([]string{"all"})
+	require.NoError(t, err)
+
+	assert.Contains(t, events, gokick.SubscriptionNameChatMessage)
+	assert.Contains(t, events, gokick.SubscriptionNameChannelRewardRedemptionUpdated)
+	for _, event := range events {
+		parsed, err := gokick.NewSubscriptionName(event.String())
+		require.NoError(t, err)
+		assert.Equal(t, event, parsed)
+	}
+}
+
+func TestSampler(t *testing.T) {
+	signer, err := gokick.GenerateWebhookSigner()
+	require.NoError(t, err)
+	verifier, err := gokick.NewWebhookVerifier(gokick.WebhookVerifierOptions{KeyProvider: signer.KeyProvider()})
+	require.NoError(t, err)
+
+	s := newSampler(1, 42, "Broadcaster")
+	for _, subscriptionName := range allSubscriptionNames() {
+		t.Run(subscriptionName.String(), func(t *testing.T) {
+			sample := s.sample(subscriptionName)
+			require.NotNil(t, sample)
+
+			request, err := signer.NewRequest(context.Background(), "http://localhost/webhook", sample)
+			require.NoError(t, err)
+			assert.Equal(t, subscriptionName.String(), request.Header.Get("Kick-Event-Type"))
+
+			event, err := verifier.GetEventFromRequest(request)
+			require.NoError(t, err)
+			assert.Equal(t, sample, event)
+		})
+	}
+
+	t.Run("seeded", func(t *testing.T) {
+		first := newSampler(7, 42, "Broadcaster").user()
+		second := newSampler(7, 42, "Broadcaster").user()
+		assert.Equal(t, first, second)
+	})
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/scorfly/gokick"
+)
+
+// stats counts the responses of the endpoint.
+type stats struct {
+	mu        sync.Mutex
+	events    map[gokick.SubscriptionName]int
+	statuses  map[int]int
+	latencies []time.Duration
+}
+
+func newStats() *stats {
+	return &stats{
+		events:   make(map[gokick.SubscriptionName]int),
+		statuses: make(map[int]int),
+	}
+}
+
+// record counts a response with statusCode, 0 when the request failed.
+func (s *stats) record(subscriptionName gokick.SubscriptionName, statusCode int, latency time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.events[subscriptionName]++
+	s.statuses[statusCode]++
+	s.latencies = append(s.latencies, latency)
+}
+
+func (s *stats) print(elapsed time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	total := len(s.latencies)
+	fmt.Printf("\nSent %d events in %s (%.1f/s)\n", total, elapsed.Round(time.Millisecond), float64(total)/elapsed.Seconds())
+	if total == 0 {
+		return
+	}
+
+	fmt.Println("\nEvents:")
+	names := make([]gokick.SubscriptionName, 0, len(s.events))
+	for name := range s.events {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	for _, name := range names {
+		fmt.Printf("  %-36s %d\n", name, s.events[name])
+	}
+
+	fmt.Println("\nResponses:")
+	codes := make([]int, 0, len(s.statuses))
+	for code := range s.statuses {
+		codes = append(codes, code)
+	}
+	slices.Sort(codes)
+	for _, code := range codes {
+		label := fmt.Sprintf("%d", code)
+		if code == 0 {
+			label = "failed"
+		}
+		fmt.Printf("  %-36s %d\n", label, s.statuses[code])
+	}
+
+	slices.Sort(s.latencies)
+	fmt.Printf("\nLatency: p50 %s, p95 %s, p99 %s, max %s\n",
+		s.percentile(50), s.percentile(95), s.percentile(99), s.latencies[total-1].Round(time.Microsecond))
+}
+
+// percentile returns the p-th percentile of the sorted latencies.
+func (s *stats) percentile(p int) time.Duration {
+	return s.latencies[(len(s.latencies)-1)*p/100].Round(time.Microsecond)
+}
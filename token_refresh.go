@@ -0,0 +1,125 @@
+package gokick
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const defaultTokenRefreshMargin = time.Minute
+
+func newTokenFromResponse(response TokenResponse) Token {
+	token := Token{
+		AccessToken:  response.AccessToken,
+		RefreshToken: response.RefreshToken,
+	}
+	if response.ExpiresIn > 0 {
+		token.ExpiresAt = time.Now().Add(time.Duration(response.ExpiresIn) * time.Second)
+	}
+
+	return token
+}
+
+// prepareToken makes sure a usable token is set before req is sent: it loads the token store,
+// refreshes an expiring user token and obtains the app access token in AutoAppAccessToken mode.
+func (c *Client) prepareToken(req *http.Request) error {
+	err := c.loadStoredToken(req.Context())
+	if err != nil {
+		return err
+	}
+
+	err = c.refreshExpiringToken(req)
+	if err != nil {
+		return err
+	}
+
+	return c.ensureAppAccessToken(req)
+}
+
+// canRenewToken reports whether the token used by requests made with ctx can be replaced after a 401.
+func (c *Client) canRenewToken(ctx context.Context) bool {
+	switch c.tokenKindFor(ctx) {
+	case tokenKindUser:
+		return c.canRefreshUserToken()
+	case tokenKindApp:
+		return c.canRenewAppToken(ctx)
+	case tokenKindExplicit:
+		return false
+	}
+
+	return false
+}
+
+// renewToken replaces the token rejected with a 401: the user token, or the automatically managed
+// app access token, depending on the token used by requests made with ctx.
+func (c *Client) renewToken(ctx context.Context, staleToken string) error {
+	if c.tokenKindFor(ctx) == tokenKindUser {
+		return c.refreshUserToken(ctx, staleToken)
+	}
+
+	return c.renewAppToken(ctx, staleToken)
+}
+
+// refreshUserToken refreshes the user token once for all concurrent callers. staleToken is the access token
+// the caller found expired or rejected: when it has already been replaced, no refresh happens.
+//
+// The refresh itself is detached from the caller's cancellation so that one cancelled caller does not fail
+// the others waiting on the same refresh; each caller still stops waiting when its own context is done.
+func (c *Client) refreshUserToken(ctx context.Context, staleToken string) error {
+	result := c.refreshGroup.DoChan("user", func() (interface{}, error) {
+		c.mu.Lock()
+		currentToken := c.options.UserAccessToken
+		c.mu.Unlock()
+
+		if currentToken != staleToken {
+			return nil, nil
+		}
+
+		return nil, c.refreshToken(context.WithoutCancel(ctx))
+	})
+
+	select {
+	case r := <-result:
+		return r.Err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// refreshExpiringToken refreshes the user token before sending req when it expires within TokenRefreshMargin.
+// A failed refresh only fails the request once the token has actually expired; until then, the request is
+// sent with the current token.
+func (c *Client) refreshExpiringToken(req *http.Request) error {
+	ctx := req.Context()
+	if ctx.Value(retryKey) != nil || isTokenEndpoint(req.URL.Path) || !c.canRefreshUserToken() ||
+		c.tokenKindFor(ctx) != tokenKindUser {
+		return nil
+	}
+
+	c.mu.Lock()
+	token := c.options.UserAccessToken
+	expiresAt := c.options.UserAccessTokenExpiresAt
+	c.mu.Unlock()
+
+	if token == "" || expiresAt.IsZero() || time.Until(expiresAt) > c.options.TokenRefreshMargin {
+		return nil
+	}
+
+	err := c.refreshUserToken(context.WithValue(ctx, retryKey, true), token)
+	if err != nil && !time.Now().Before(expiresAt) {
+		return err
+	}
+
+	return nil
+}
+
+// isTokenEndpoint reports whether path is an OAuth endpoint issuing or revoking tokens, which must not
+// trigger a user token refresh.
+func isTokenEndpoint(path string) bool {
+	return path == "/oauth/token" || path == "/oauth/revoke"
+}
+
+func bearerToken(req *http.Request) string {
+	return strings.TrimPrefix(req.Header.Get("Authorization"), "Bearer ")
+}
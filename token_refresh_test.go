@@ -0,0 +1,172 @@
+package gokick_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/scorfly/gokick"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type tokenRefreshServer struct {
+	refreshes atomic.Int32
+	mu        sync.Mutex
+	tokens    map[string]int
+	failAuth  bool
+}
+
+func newTokenRefreshServer() *tokenRefreshServer {
+	return &tokenRefreshServer{tokens: make(map[string]int)}
+}
+
+// ServeHTTP accepts any bearer token on the API and records which ones were used.
+func (s *tokenRefreshServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path == "/oauth/token" {
+		s.refreshes.Add(1)
+		time.Sleep(20 * time.Millisecond)
+		if s.failAuth {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(w, `{"error":"invalid_grant"}`)
+			return
+		}
+		fmt.Fprint(w, `{"access_token":"new-access","refresh_token":"new-refresh","expires_in":7200,"token_type":"Bearer"}`)
+		return
+	}
+
+	s.mu.Lock()
+	s.tokens[r.Header.Get("Authorization")]++
+	s.mu.Unlock()
+
+	fmt.Fprint(w, `{"data":[]}`)
+}
+
+func newExpiringTokenClient(t *testing.T, server *tokenRefreshServer, expiresAt time.Time) *gokick.Client {
+	t.Helper()
+
+	return setupMockClientWithOptions(t, gokick.ClientOptions{
+		UserAccessToken:          "old-access",
+		UserRefreshToken:         "old-refresh",
+		UserAccessTokenExpiresAt: expiresAt,
+		ClientID:                 "client-id",
+		ClientSecret:             "client-secret",
+	}, server.ServeHTTP)
+}
+
+func TestProactiveTokenRefresh(t *testing.T) {
+	t.Run("concurrent requests refresh once", func(t *testing.T) {
+		server := newTokenRefreshServer()
+		kickClient := newExpiringTokenClient(t, server, time.Now().Add(30*time.Second))
+
+		var wg sync.WaitGroup
+		for range 50 {
+			wg.Go(func() {
+				_, err := kickClient.GetUsers(context.Background(), gokick.NewUserListFilter())
+				assert.NoError(t, err)
+			})
+		}
+		wg.Wait()
+
+		assert.Equal(t, int32(1), server.refreshes.Load())
+		assert.Equal(t, map[string]int{"Bearer new-access": 50}, server.tokens)
+	})
+
+	t.Run("token far from expiry is kept", func(t *testing.T) {
+		server := newTokenRefreshServer()
+		kickClient := newExpiringTokenClient(t, server, time.Now().Add(time.Hour))
+
+		_, err := kickClient.GetUsers(context.Background(), gokick.NewUserListFilter())
+		require.NoError(t, err)
+
+		assert.Equal(t, int32(0), server.refreshes.Load())
+		assert.Equal(t, map[string]int{"Bearer old-access": 1}, server.tokens)
+	})
+
+	t.Run("unknown expiry waits for a 401", func(t *testing.T) {
+		server := newTokenRefreshServer()
+		kickClient := newExpiringTokenClient(t, server, time.Now())
+		kickClient.SetUserAccessToken("old-access")
+
+		_, err := kickClient.GetUsers(context.Background(), gokick.NewUserListFilter())
+		require.NoError(t, err)
+		assert.Equal(t, int32(0), server.refreshes.Load())
+
+		kickClient.SetUserAccessTokenExpiresAt(time.Now().Add(time.Second))
+
+		_, err = kickClient.GetUsers(context.Background(), gokick.NewUserListFilter())
+		require.NoError(t, err)
+		assert.Equal(t, int32(1), server.refreshes.Load())
+	})
+
+	t.Run("refreshed token expiry is tracked", func(t *testing.T) {
+		server := newTokenRefreshServer()
+		kickClient := newExpiringTokenClient(t, server, time.Now())
+
+		for range 3 {
+			_, err := kickClient.GetUsers(context.Background(), gokick.NewUserListFilter())
+			require.NoError(t, err)
+		}
+
+		assert.Equal(t, int32(1), server.refreshes.Load())
+	})
+
+	t.Run("failed refresh before expiry keeps the current token", func(t *testing.T) {
+		server := newTokenRefreshServer()
+		server.failAuth = true
+		kickClient := newExpiringTokenClient(t, server, time.Now().Add(30*time.Second))
+
+		_, err := kickClient.GetUsers(context.Background(), gokick.NewUserListFilter())
+		require.NoError(t, err)
+		assert.Equal(t, map[string]int{"Bearer old-access": 1}, server.tokens)
+	})
+
+	t.Run("failed refresh after expiry fails the request", func(t *testing.T) {
+		server := newTokenRefreshServer()
+		server.failAuth = true
+		kickClient := newExpiringTokenClient(t, server, time.Now().Add(-time.Second))
+
+		_, err := kickClient.GetUsers(context.Background(), gokick.NewUserListFilter())
+		require.EqualError(t, err, "failed to make request: failed to refresh token: Error 400: invalid_grant")
+		assert.Empty(t, server.tokens)
+	})
+}
+
+func TestUnauthorizedTokenRefresh(t *testing.T) {
+	t.Run("concurrent 401s refresh once", func(t *testing.T) {
+		var refreshes atomic.Int32
+		kickClient := newTokenStoreClient(t, tokenStoreHandler("new-access", &refreshes), "old-access", nil)
+
+		var wg sync.WaitGroup
+		for range 50 {
+			wg.Go(func() {
+				_, err := kickClient.GetUsers(context.Background(), gokick.NewUserListFilter())
+				assert.NoError(t, err)
+			})
+		}
+		wg.Wait()
+
+		assert.Equal(t, int32(1), refreshes.Load())
+	})
+
+	t.Run("refresh happens once per request", func(t *testing.T) {
+		var refreshes atomic.Int32
+		kickClient := newTokenStoreClient(t, func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/oauth/token" {
+				refreshes.Add(1)
+				fmt.Fprint(w, `{"access_token":"new-access","refresh_token":"new-refresh","expires_in":7200}`)
+				return
+			}
+			w.WriteHeader(http.StatusUnauthorized)
+			fmt.Fprint(w, `{"message":"unauthorized"}`)
+		}, "old-access", nil)
+
+		_, err := kickClient.GetUsers(context.Background(), gokick.NewUserListFilter())
+		require.EqualError(t, err, "Error 401: unauthorized")
+		assert.Equal(t, int32(1), refreshes.Load())
+	})
+}
@@ -0,0 +1,82 @@
+package gokick
+
+import (
+	"context"
+)
+
+type tokenKind int
+
+const (
+	tokenKindUser tokenKind = iota + 1
+	tokenKindApp
+	tokenKindExplicit
+)
+
+const tokenSelectionKey contextKey = "token"
+
+type tokenSelection struct {
+	kind  tokenKind
+	token string
+}
+
+// WithAppAccessToken makes requests made with ctx use the app access token, even when a user access token is set.
+func WithAppAccessToken(ctx context.Context) context.Context {
+	return context.WithValue(ctx, tokenSelectionKey, tokenSelection{kind: tokenKindApp})
+}
+
+// WithUserAccessToken makes requests made with ctx use the user access token of the client.
+func WithUserAccessToken(ctx context.Context) context.Context {
+	return context.WithValue(ctx, tokenSelectionKey, tokenSelection{kind: tokenKindUser})
+}
+
+// WithAccessToken makes requests made with ctx use token, for example the user access token of another
+// broadcaster. The client never refreshes an explicit token: a 401 is returned to the caller.
+func WithAccessToken(ctx context.Context, token string) context.Context {
+	return context.WithValue(ctx, tokenSelectionKey, tokenSelection{kind: tokenKindExplicit, token: token})
+}
+
+// contextTokenSelection returns the token selection of ctx, zero when requests use the default token.
+func contextTokenSelection(ctx context.Context) tokenSelection {
+	selection, _ := ctx.Value(tokenSelectionKey).(tokenSelection)
+	return selection
+}
+
+// withTokenSelection returns a context making requests use the token of selection.
+func withTokenSelection(ctx context.Context, selection tokenSelection) context.Context {
+	if selection.kind == 0 {
+		return ctx
+	}
+
+	return context.WithValue(ctx, tokenSelectionKey, selection)
+}
+
+// selectToken returns which token requests made with ctx use, and its current value.
+// Without a selection, the user access token is preferred over the app access token. c.mu must be held.
+func (c *Client) selectToken(ctx context.Context) (tokenKind, string) {
+	selection := contextTokenSelection(ctx)
+
+	switch selection.kind {
+	case tokenKindExplicit:
+		return tokenKindExplicit, selection.token
+	case tokenKindApp:
+		return tokenKindApp, c.options.AppAccessToken
+	case tokenKindUser:
+		return tokenKindUser, c.options.UserAccessToken
+	}
+
+	if c.options.UserAccessToken != "" {
+		return tokenKindUser, c.options.UserAccessToken
+	}
+
+	return tokenKindApp, c.options.AppAccessToken
+}
+
+// tokenKindFor returns which token requests made with ctx use.
+func (c *Client) tokenKindFor(ctx context.Context) tokenKind {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	kind, _ := c.selectToken(ctx)
+
+	return kind
+}
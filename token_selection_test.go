@@ -0,0 +1,69 @@
+package gokick_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync/atomic"
+	"testing"
+
+	"github.com/scorfly/gokick"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTokenSelection(t *testing.T) {
+	var authorization string
+	kickClient := setupMockClient(t, func(w http.ResponseWriter, r *http.Request) {
+		authorization = r.Header.Get("Authorization")
+		fmt.Fprint(w, `{"data":[]}`)
+	})
+	kickClient.SetAppAccessToken("app-access")
+	kickClient.SetUserAccessToken("user-access")
+
+	testCases := map[string]struct {
+		ctx      context.Context
+		expected string
+	}{
+		"default prefers the user token": {ctx: context.Background(), expected: "Bearer user-access"},
+		"app token":                      {ctx: gokick.WithAppAccessToken(context.Background()), expected: "Bearer app-access"},
+		"user token":                     {ctx: gokick.WithUserAccessToken(context.Background()), expected: "Bearer user-access"},
+		"explicit token": {
+			ctx:      gokick.WithAccessToken(context.Background(), "broadcaster-access"),
+			expected: "Bearer broadcaster-access",
+		},
+		"last selection wins": {
+			ctx:      gokick.WithAppAccessToken(gokick.WithAccessToken(context.Background(), "broadcaster-access")),
+			expected: "Bearer app-access",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			_, err := kickClient.GetUsers(tc.ctx, gokick.NewUserListFilter())
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, authorization)
+		})
+	}
+}
+
+func TestTokenSelectionRenewal(t *testing.T) {
+	t.Run("explicit token is not refreshed", func(t *testing.T) {
+		var refreshes atomic.Int32
+		kickClient := newTokenStoreClient(t, tokenStoreHandler("new-access", &refreshes), "new-access", nil)
+
+		_, err := kickClient.GetUsers(gokick.WithAccessToken(context.Background(), "other-access"), gokick.NewUserListFilter())
+		require.EqualError(t, err, "Error 401: unauthorized")
+		assert.Equal(t, int32(0), refreshes.Load())
+	})
+
+	t.Run("app token is managed next to a user token", func(t *testing.T) {
+		var issued atomic.Int32
+		kickClient := newAutoAppTokenClient(t, appTokenHandler(3600, &issued))
+		kickClient.SetUserAccessToken("user-access")
+
+		_, err := kickClient.GetChannels(gokick.WithAppAccessToken(context.Background()), gokick.NewChannelListFilter())
+		require.NoError(t, err)
+		assert.Equal(t, int32(1), issued.Load())
+	})
+}
@@ -0,0 +1,316 @@
+package gokick
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// ErrTokenNotFound is returned by TokenStore.Load when no token has been saved yet.
+var ErrTokenNotFound = errors.New("gokick: token not found in store")
+
+// Token is the user token pair persisted by a TokenStore.
+type Token struct {
+	AccessToken  string    `json:"access_token"`
+	RefreshToken string    `json:"refresh_token"`
+	ExpiresAt    time.Time `json:"expires_at,omitzero"`
+}
+
+// TokenStore persists the user token pair so it survives restarts and can be shared between processes
+// using the same bot account.
+//
+// When ClientOptions.TokenStore is set, Client takes the lock before refreshing the user token, reloads the
+// stored token, and only calls the refresh endpoint if no other holder refreshed it in the meantime.
+// This prevents several processes from invalidating each other's refresh tokens.
+type TokenStore interface {
+	// Load returns the stored token, or ErrTokenNotFound.
+	Load(ctx context.Context) (Token, error)
+	// Save stores the token.
+	Save(ctx context.Context, token Token) error
+	// Lock acquires exclusive access to the token until unlock is called, or fails once ctx is done.
+	Lock(ctx context.Context) (unlock func(), err error)
+}
+
+// MemoryTokenStore is a TokenStore shared by the clients of a single process.
+type MemoryTokenStore struct {
+	mu    sync.Mutex
+	lock  chan struct{}
+	token *Token
+}
+
+func NewMemoryTokenStore() *MemoryTokenStore {
+	return &MemoryTokenStore{lock: make(chan struct{}, 1)}
+}
+
+func (s *MemoryTokenStore) Load(_ context.Context) (Token, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.token == nil {
+		return Token{}, ErrTokenNotFound
+	}
+
+	return *s.token, nil
+}
+
+func (s *MemoryTokenStore) Save(_ context.Context, token Token) error {
+	s.mu.Lock()
+	s.token = &token
+	s.mu.Unlock()
+
+	return nil
+}
+
+func (s *MemoryTokenStore) Lock(ctx context.Context) (func(), error) {
+	select {
+	case s.lock <- struct{}{}:
+		return func() { <-s.lock }, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+const (
+	fileTokenStoreLockPollInterval = 50 * time.Millisecond
+	fileTokenStoreStaleLockAfter   = 30 * time.Second
+	fileTokenStoreLockKeepAlive    = 10 * time.Second
+)
+
+// FileTokenStore is a TokenStore backed by a JSON file (mode 0600), shareable between processes on the same host.
+//
+// Locking relies on a sibling "<path>.lock" file created exclusively, holding a random owner token. The holder
+// touches the file every 10 seconds until it unlocks, so a lock not touched for 30 seconds is considered left
+// behind by a crashed process and is taken over. Unlocking only removes the file while it still holds the owner
+// token, never the lock of a process that took it over.
+type FileTokenStore struct {
+	path string
+}
+
+func NewFileTokenStore(path string) *FileTokenStore {
+	return &FileTokenStore{path: path}
+}
+
+func (s *FileTokenStore) Load(_ context.Context) (Token, error) {
+	content, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return Token{}, ErrTokenNotFound
+	}
+	if err != nil {
+		return Token{}, fmt.Errorf("failed to read token file: %w", err)
+	}
+
+	var token Token
+	err = json.Unmarshal(content, &token)
+	if err != nil {
+		return Token{}, fmt.Errorf("failed to unmarshal token file: %w", err)
+	}
+
+	return token, nil
+}
+
+func (s *FileTokenStore) Save(_ context.Context, token Token) error {
+	content, err := json.Marshal(token)
+	if err != nil {
+		return fmt.Errorf("failed to marshal token: %w", err)
+	}
+
+	// Write to a temporary file then rename it, so readers never see a partially written token.
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create token file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	_, err = tmp.Write(content)
+	if err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write token file: %w", err)
+	}
+
+	err = tmp.Chmod(0o600)
+	if err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write token file: %w", err)
+	}
+
+	err = tmp.Close()
+	if err != nil {
+		return fmt.Errorf("failed to write token file: %w", err)
+	}
+
+	err = os.Rename(tmp.Name(), s.path)
+	if err != nil {
+		return fmt.Errorf("failed to write token file: %w", err)
+	}
+
+	return nil
+}
+
+func (s *FileTokenStore) Lock(ctx context.Context) (func(), error) {
+	lockPath := s.path + ".lock"
+
+	for {
+		lockFile, err := os.OpenFile(lockPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600)
+		if err == nil {
+			owner := rand.Text()
+			_, err = lockFile.WriteString(owner)
+			lockFile.Close()
+			if err != nil {
+				os.Remove(lockPath)
+				return nil, fmt.Errorf("failed to write token lock file: %w", err)
+			}
+
+			return holdFileLock(lockPath, owner), nil
+		}
+
+		if !errors.Is(err, os.ErrExist) {
+			return nil, fmt.Errorf("failed to create token lock file: %w", err)
+		}
+
+		info, err := os.Stat(lockPath)
+		if err == nil && time.Since(info.ModTime()) > fileTokenStoreStaleLockAfter {
+			os.Remove(lockPath)
+			continue
+		}
+
+		err = sleepContext(ctx, fileTokenStoreLockPollInterval)
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+// holdFileLock touches the lock file while it is held, so that it is not taken over as stale, and returns the
+// function releasing it.
+func holdFileLock(lockPath, owner string) func() {
+	owned := func() bool {
+		content, err := os.ReadFile(lockPath)
+		return err == nil && string(content) == owner
+	}
+
+	done := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(fileTokenStoreLockKeepAlive)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				if owned() {
+					now := time.Now()
+					_ = os.Chtimes(lockPath, now, now)
+				}
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			close(done)
+			if owned() {
+				os.Remove(lockPath)
+			}
+		})
+	}
+}
+
+// refreshTokenWithStore refreshes the user token under the store lock. If another holder of the store
+// refreshed the token since this client last used it, the stored token is adopted without calling Kick.
+func (c *Client) refreshTokenWithStore(ctx context.Context, store TokenStore) error {
+	unlock, err := store.Lock(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to lock token store: %w", err)
+	}
+	defer unlock()
+
+	stored, err := store.Load(ctx)
+	if err != nil && !errors.Is(err, ErrTokenNotFound) {
+		return fmt.Errorf("failed to load token from store: %w", err)
+	}
+
+	c.mu.Lock()
+	currentAccessToken := c.options.UserAccessToken
+	refreshToken := c.options.UserRefreshToken
+	c.mu.Unlock()
+
+	if stored.AccessToken != "" && stored.AccessToken != currentAccessToken {
+		c.setUserToken(stored)
+		return nil
+	}
+
+	if stored.RefreshToken != "" {
+		refreshToken = stored.RefreshToken
+	}
+
+	response, err := c.RefreshToken(ctx, refreshToken)
+	ContextTrace(ctx).tokenRefreshed("user", err)
+	if err != nil {
+		return fmt.Errorf("failed to refresh token: %w", err)
+	}
+
+	token := newTokenFromResponse(response)
+
+	err = store.Save(ctx, token)
+	if err != nil {
+		return fmt.Errorf("failed to save token to store: %w", err)
+	}
+
+	c.setUserToken(token)
+
+	return nil
+}
+
+// loadStoredToken initializes the user token from the store when the client was created without one.
+func (c *Client) loadStoredToken(ctx context.Context) error {
+	store := c.options.TokenStore
+
+	c.mu.Lock()
+	hasToken := c.options.UserAccessToken != ""
+	c.mu.Unlock()
+
+	if store == nil || hasToken {
+		return nil
+	}
+
+	token, err := store.Load(ctx)
+	if errors.Is(err, ErrTokenNotFound) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to load token from store: %w", err)
+	}
+
+	c.mu.Lock()
+	c.options.UserAccessToken = token.AccessToken
+	c.options.UserRefreshToken = token.RefreshToken
+	c.options.UserAccessTokenExpiresAt = token.ExpiresAt
+	c.mu.Unlock()
+
+	return nil
+}
+
+func (c *Client) setUserToken(token Token) {
+	c.mu.Lock()
+	c.options.UserAccessToken = token.AccessToken
+	c.options.UserRefreshToken = token.RefreshToken
+	c.options.UserAccessTokenExpiresAt = token.ExpiresAt
+	persist := c.callbacks.onUserAccessTokenPersist
+	callback := c.callbacks.onUserAccessTokenRefreshed
+	c.mu.Unlock()
+
+	if persist != nil {
+		persist(token.AccessToken, token.RefreshToken)
+	}
+	if callback != nil {
+		go callback(token.AccessToken, token.RefreshToken)
+	}
+}
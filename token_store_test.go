@@ -0,0 +1,261 @@
+package gokick_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/scorfly/gokick"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryTokenStore(t *testing.T) {
+	store := gokick.NewMemoryTokenStore()
+
+	_, err := store.Load(context.Background())
+	require.ErrorIs(t, err, gokick.ErrTokenNotFound)
+
+	require.NoError(t, store.Save(context.Background(), gokick.Token{AccessToken: "access", RefreshToken: "refresh"}))
+
+	token, err := store.Load(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, gokick.Token{AccessToken: "access", RefreshToken: "refresh"}, token)
+
+	unlock, err := store.Lock(context.Background())
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	_, err = store.Lock(ctx)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	unlock()
+
+	unlock, err = store.Lock(context.Background())
+	require.NoError(t, err)
+	unlock()
+}
+
+func TestFileTokenStore(t *testing.T) {
+	t.Run("save and load", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "token.json")
+		store := gokick.NewFileTokenStore(path)
+
+		_, err := store.Load(context.Background())
+		require.ErrorIs(t, err, gokick.ErrTokenNotFound)
+
+		expiresAt := time.Date(2025, 2, 21, 23, 23, 36, 0, time.UTC)
+		saved := gokick.Token{AccessToken: "access", RefreshToken: "refresh", ExpiresAt: expiresAt}
+		require.NoError(t, store.Save(context.Background(), saved))
+
+		info, err := os.Stat(path)
+		require.NoError(t, err)
+		assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+
+		token, err := store.Load(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, "access", token.AccessToken)
+		assert.Equal(t, "refresh", token.RefreshToken)
+		assert.True(t, expiresAt.Equal(token.ExpiresAt))
+	})
+
+	t.Run("invalid file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "token.json")
+		require.NoError(t, os.WriteFile(path, []byte("not json"), 0o600))
+
+		_, err := gokick.NewFileTokenStore(path).Load(context.Background())
+		require.ErrorContains(t, err, "failed to unmarshal token file")
+	})
+
+	t.Run("unreadable file", func(t *testing.T) {
+		_, err := gokick.NewFileTokenStore(t.TempDir()).Load(context.Background())
+		require.ErrorContains(t, err, "failed to read token file")
+	})
+
+	t.Run("save in a missing directory", func(t *testing.T) {
+		store := gokick.NewFileTokenStore(filepath.Join(t.TempDir(), "missing", "token.json"))
+
+		err := store.Save(context.Background(), gokick.Token{})
+		require.ErrorContains(t, err, "failed to create token file")
+	})
+
+	t.Run("lock", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "token.json")
+		store := gokick.NewFileTokenStore(path)
+
+		unlock, err := store.Lock(context.Background())
+		require.NoError(t, err)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+
+		_, err = gokick.NewFileTokenStore(path).Lock(ctx)
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+
+		unlock()
+
+		unlock, err = gokick.NewFileTokenStore(path).Lock(context.Background())
+		require.NoError(t, err)
+		unlock()
+	})
+
+	t.Run("stale lock is taken over", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "token.json")
+		require.NoError(t, os.WriteFile(path+".lock", nil, 0o600))
+
+		old := time.Now().Add(-time.Hour)
+		require.NoError(t, os.Chtimes(path+".lock", old, old))
+
+		unlock, err := gokick.NewFileTokenStore(path).Lock(context.Background())
+		require.NoError(t, err)
+		unlock()
+	})
+
+	t.Run("unlock keeps the lock of the holder that took it over", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "token.json")
+
+		unlockStale, err := gokick.NewFileTokenStore(path).Lock(context.Background())
+		require.NoError(t, err)
+		old := time.Now().Add(-time.Hour)
+		require.NoError(t, os.Chtimes(path+".lock", old, old))
+
+		unlock, err := gokick.NewFileTokenStore(path).Lock(context.Background())
+		require.NoError(t, err)
+		unlockStale()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+		_, err = gokick.NewFileTokenStore(path).Lock(ctx)
+		require.ErrorIs(t, err, context.DeadlineExceeded, "the lock is still held")
+
+		unlock()
+		unlock, err = gokick.NewFileTokenStore(path).Lock(context.Background())
+		require.NoError(t, err)
+		unlock()
+	})
+
+	t.Run("lock in a missing directory", func(t *testing.T) {
+		store := gokick.NewFileTokenStore(filepath.Join(t.TempDir(), "missing", "token.json"))
+
+		_, err := store.Lock(context.Background())
+		require.ErrorContains(t, err, "failed to create token lock file")
+	})
+}
+
+// tokenStoreHandler serves both the API and OAuth endpoints. API calls succeed only with validAccessToken,
+// and every refresh returns validAccessToken.
+func tokenStoreHandler(validAccessToken string, refreshes *atomic.Int32) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/oauth/token" {
+			refreshes.Add(1)
+			fmt.Fprintf(w, `{"access_token":%q,"refresh_token":"new-refresh","expires_in":7200,"token_type":"Bearer"}`, validAccessToken)
+			return
+		}
+
+		if r.Header.Get("Authorization") != "Bearer "+validAccessToken {
+			w.WriteHeader(http.StatusUnauthorized)
+			fmt.Fprint(w, `{"message":"unauthorized"}`)
+			return
+		}
+
+		fmt.Fprint(w, `{"data":[]}`)
+	}
+}
+
+func newTokenStoreClient(t *testing.T, handler http.HandlerFunc, accessToken string, store gokick.TokenStore) *gokick.Client {
+	t.Helper()
+
+	return setupMockClientWithOptions(t, gokick.ClientOptions{
+		UserAccessToken:  accessToken,
+		UserRefreshToken: "old-refresh",
+		ClientID:         "client-id",
+		ClientSecret:     "client-secret",
+		TokenStore:       store,
+	}, handler)
+}
+
+func TestClientTokenStore(t *testing.T) {
+	t.Run("refresh saves to the store", func(t *testing.T) {
+		var refreshes atomic.Int32
+		handler := tokenStoreHandler("new-access", &refreshes)
+		store := gokick.NewMemoryTokenStore()
+
+		refreshed := make(chan string, 1)
+		kickClient := newTokenStoreClient(t, handler, "old-access", store)
+		kickClient.OnUserAccessTokenRefreshed(func(accessToken, _ string) { refreshed <- accessToken })
+
+		_, err := kickClient.GetUsers(context.Background(), gokick.NewUserListFilter())
+		require.NoError(t, err)
+
+		token, err := store.Load(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, "new-access", token.AccessToken)
+		assert.Equal(t, "new-refresh", token.RefreshToken)
+		assert.False(t, token.ExpiresAt.IsZero())
+		assert.Equal(t, int32(1), refreshes.Load())
+		assert.Equal(t, "new-access", <-refreshed)
+	})
+
+	t.Run("token refreshed elsewhere is adopted", func(t *testing.T) {
+		var refreshes atomic.Int32
+		handler := tokenStoreHandler("new-access", &refreshes)
+		store := gokick.NewMemoryTokenStore()
+		require.NoError(t, store.Save(context.Background(), gokick.Token{AccessToken: "new-access", RefreshToken: "new-refresh"}))
+
+		kickClient := newTokenStoreClient(t, handler, "old-access", store)
+
+		_, err := kickClient.GetUsers(context.Background(), gokick.NewUserListFilter())
+		require.NoError(t, err)
+		assert.Equal(t, int32(0), refreshes.Load())
+	})
+
+	t.Run("token is loaded from the store", func(t *testing.T) {
+		var refreshes atomic.Int32
+		handler := tokenStoreHandler("stored-access", &refreshes)
+		store := gokick.NewFileTokenStore(filepath.Join(t.TempDir(), "token.json"))
+		require.NoError(t, store.Save(context.Background(), gokick.Token{AccessToken: "stored-access", RefreshToken: "stored-refresh"}))
+
+		kickClient := newTokenStoreClient(t, handler, "", store)
+
+		_, err := kickClient.GetUsers(context.Background(), gokick.NewUserListFilter())
+		require.NoError(t, err)
+		assert.Equal(t, int32(0), refreshes.Load())
+	})
+
+	t.Run("clients sharing a store refresh once", func(t *testing.T) {
+		var refreshes atomic.Int32
+		handler := tokenStoreHandler("new-access", &refreshes)
+		store := gokick.NewFileTokenStore(filepath.Join(t.TempDir(), "token.json"))
+
+		var wg sync.WaitGroup
+		for range 5 {
+			kickClient := newTokenStoreClient(t, handler, "old-access", store)
+			wg.Go(func() {
+				_, err := kickClient.GetUsers(context.Background(), gokick.NewUserListFilter())
+				assert.NoError(t, err)
+			})
+		}
+		wg.Wait()
+
+		assert.Equal(t, int32(1), refreshes.Load())
+	})
+
+	t.Run("store lock error", func(t *testing.T) {
+		var refreshes atomic.Int32
+		handler := tokenStoreHandler("new-access", &refreshes)
+		store := gokick.NewFileTokenStore(filepath.Join(t.TempDir(), "missing", "token.json"))
+
+		kickClient := newTokenStoreClient(t, handler, "old-access", store)
+
+		_, err := kickClient.GetUsers(context.Background(), gokick.NewUserListFilter())
+		require.ErrorContains(t, err, "failed to lock token store: failed to create token lock file")
+	})
+}
@@ -0,0 +1,108 @@
+package gokick
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+const traceKey contextKey = "trace"
+
+// Trace is a set of hooks run at the stages of client calls and webhook processing made with a context carrying it,
+// to feed tracing or metrics. Any hook may be nil. Hooks may be called concurrently.
+type Trace struct {
+	// Retry is called before a call is sent again. response is nil when the attempt failed with err.
+	Retry func(attempt int, delay time.Duration, response *http.Response, err error)
+	// TokenRefreshed is called once Kick answered a refresh of the "user" or "app" token, in the call that started
+	// it. Calls sharing that refresh, and calls finding the token already replaced, do not run it.
+	TokenRefreshed func(kind string, err error)
+
+	// WebhookVerifyStart and WebhookVerifyDone surround the signature verification of a webhook event.
+	WebhookVerifyStart func()
+	WebhookVerifyDone  func(err error)
+	// WebhookParseStart and WebhookParseDone surround the decoding of a webhook event into its type.
+	WebhookParseStart func(subscriptionName SubscriptionName, version string)
+	WebhookParseDone  func(err error)
+	// WebhookDispatchStart and WebhookDispatchDone surround the call of the EventHandler callbacks.
+	// WebhookDispatchStart returns the context the callbacks are called with, derived from ctx, for example to
+	// carry a span; returning nil keeps ctx.
+	WebhookDispatchStart func(ctx context.Context, meta EventMeta) context.Context
+	WebhookDispatchDone  func(err error)
+}
+
+// WithTrace returns a context running the hooks of trace.
+func WithTrace(ctx context.Context, trace *Trace) context.Context {
+	return context.WithValue(ctx, traceKey, trace)
+}
+
+// ContextTrace returns the Trace of ctx, or nil.
+func ContextTrace(ctx context.Context) *Trace {
+	trace, _ := ctx.Value(traceKey).(*Trace)
+	return trace
+}
+
+func (t *Trace) retry(attempt int, delay time.Duration, response *http.Response, err error) {
+	if t != nil && t.Retry != nil {
+		t.Retry(attempt, delay, response, err)
+	}
+}
+
+func (t *Trace) tokenRefreshed(kind string, err error) {
+	if t != nil && t.TokenRefreshed != nil {
+		t.TokenRefreshed(kind, err)
+	}
+}
+
+// verifyEventSignature verifies the signature of a webhook event between the verify hooks of t.
+func (t *Trace) verifyEventSignature(
+	ctx context.Context,
+	verifier *WebhookVerifier,
+	eventSignature, messageID, timestamp, body string,
+) error {
+	if t != nil && t.WebhookVerifyStart != nil {
+		t.WebhookVerifyStart()
+	}
+
+	err := verifier.verifySignature(ctx, eventSignature, messageID, timestamp, body)
+
+	if t != nil && t.WebhookVerifyDone != nil {
+		t.WebhookVerifyDone(err)
+	}
+
+	return err
+}
+
+// parseEvent decodes a webhook event between the parse hooks of t.
+func (t *Trace) parseEvent(subscriptionName SubscriptionName, version string, body string) (interface{}, error) {
+	if t != nil && t.WebhookParseStart != nil {
+		t.WebhookParseStart(subscriptionName, version)
+	}
+
+	event, err := parseEvent(subscriptionName, version, body)
+
+	if t != nil && t.WebhookParseDone != nil {
+		t.WebhookParseDone(err)
+	}
+
+	return event, err
+}
+
+// dispatchStart runs the dispatch start hook of t and returns the context of the callbacks.
+func (t *Trace) dispatchStart(ctx context.Context, meta EventMeta) context.Context {
+	if t == nil || t.WebhookDispatchStart == nil {
+		return ctx
+	}
+
+	dispatchCtx := t.WebhookDispatchStart(ctx, meta)
+	if dispatchCtx == nil {
+		return ctx
+	}
+
+	return dispatchCtx
+}
+
+func (t *Trace) dispatchDone(err error) {
+	if t != nil && t.WebhookDispatchDone != nil {
+		t.WebhookDispatchDone(err)
+	}
+}
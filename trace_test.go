@@ -0,0 +1,144 @@
+package gokick_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/scorfly/gokick"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingTrace returns a Trace appending a line per hook to events.
+func recordingTrace(events *[]string) *gokick.Trace {
+	var mu sync.Mutex
+	record := func(format string, args ...interface{}) {
+		mu.Lock()
+		*events = append(*events, fmt.Sprintf(format, args...))
+		mu.Unlock()
+	}
+
+	return &gokick.Trace{
+		Retry: func(attempt int, _ time.Duration, response *http.Response, err error) {
+			if response != nil {
+				record("retry %d after status %d", attempt, response.StatusCode)
+				return
+			}
+			record("retry %d after error %t", attempt, err != nil)
+		},
+		TokenRefreshed:     func(kind string, err error) { record("%s token refreshed: %v", kind, err) },
+		WebhookVerifyStart: func() { record("verify start") },
+		WebhookVerifyDone:  func(err error) { record("verify done: %v", err) },
+		WebhookParseStart:  func(name gokick.SubscriptionName, version string) { record("parse start %s %s", name, version) },
+		WebhookParseDone:   func(err error) { record("parse done: %v", err) },
+		WebhookDispatchStart: func(_ context.Context, meta gokick.EventMeta) context.Context {
+			record("dispatch start %s", meta.MessageID)
+			return nil
+		},
+		WebhookDispatchDone: func(err error) { record("dispatch done: %v", err) },
+	}
+}
+
+func TestTraceClient(t *testing.T) {
+	t.Run("retries", func(t *testing.T) {
+		var calls atomic.Int32
+		kickClient := setupMockClientWithOptions(t, retryClientOptions(fastRetryPolicy()), func(w http.ResponseWriter, _ *http.Request) {
+			if calls.Add(1) == 1 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			fmt.Fprint(w, `{"data":[]}`)
+		})
+
+		var events []string
+		ctx := gokick.WithTrace(context.Background(), recordingTrace(&events))
+		_, err := kickClient.GetUsers(ctx, gokick.NewUserListFilter())
+		require.NoError(t, err)
+		assert.Equal(t, []string{"retry 1 after status 503"}, events)
+	})
+
+	t.Run("token refresh", func(t *testing.T) {
+		var refreshes atomic.Int32
+		kickClient := newTokenStoreClient(t, tokenStoreHandler("new-access", &refreshes), "old-access", nil)
+
+		var events []string
+		ctx := gokick.WithTrace(context.Background(), recordingTrace(&events))
+		_, err := kickClient.GetUsers(ctx, gokick.NewUserListFilter())
+		require.NoError(t, err)
+		assert.Equal(t, []string{"user token refreshed: <nil>"}, events)
+	})
+
+	t.Run("token refresh shared by concurrent calls", func(t *testing.T) {
+		var refreshes atomic.Int32
+		kickClient := newTokenStoreClient(t, tokenStoreHandler("new-access", &refreshes), "old-access", nil)
+
+		var events []string
+		ctx := gokick.WithTrace(context.Background(), recordingTrace(&events))
+		var wg sync.WaitGroup
+		for range 20 {
+			wg.Go(func() {
+				_, err := kickClient.GetUsers(ctx, gokick.NewUserListFilter())
+				assert.NoError(t, err)
+			})
+		}
+		wg.Wait()
+
+		assert.Equal(t, int32(1), refreshes.Load())
+		assert.Equal(t, []string{"user token refreshed: <nil>"}, events)
+	})
+
+	t.Run("no trace", func(t *testing.T) {
+		assert.Nil(t, gokick.ContextTrace(context.Background()))
+
+		kickClient := setupMockClientWithOptions(t, retryClientOptions(fastRetryPolicy()), func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		})
+
+		_, err := kickClient.GetUsers(gokick.WithTrace(context.Background(), &gokick.Trace{}), gokick.NewUserListFilter())
+		require.Error(t, err)
+	})
+}
+
+func TestTraceWebhook(t *testing.T) {
+	skipSignatureValidation(t)
+
+	t.Run("GetEventFromRequest", func(t *testing.T) {
+		var events []string
+		req := newWebhookRequest(t, "channel.followed", "1", `{}`)
+		req = req.WithContext(gokick.WithTrace(req.Context(), recordingTrace(&events)))
+
+		_, err := gokick.GetEventFromRequest(req)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"verify start", "verify done: <nil>", "parse start channel.followed 1", "parse done: <nil>"}, events)
+	})
+
+	t.Run("EventHandler", func(t *testing.T) {
+		handler := gokick.NewEventHandler()
+		handler.OnChannelFollow(func(context.Context, *gokick.ChannelFollowEvent, gokick.EventMeta) error {
+			return errors.New("boom")
+		})
+
+		var events []string
+		req := newWebhookRequest(t, "channel.followed", "1", `{}`)
+		req = req.WithContext(gokick.WithTrace(req.Context(), recordingTrace(&events)))
+
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, req)
+		assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+		assert.Equal(
+			t,
+			[]string{
+				"verify start", "verify done: <nil>", "parse start channel.followed 1", "parse done: <nil>",
+				"dispatch start 01JMND5PSxxxxxx", "dispatch done: boom",
+			},
+			events,
+		)
+	})
+}
@@ -1,7 +1,7 @@
 package gokick
 
 import (
-	"bytes"
+	"context"
 	"crypto"
 	"crypto/rsa"
 	"crypto/sha256"
@@ -11,7 +11,6 @@ import (
 	"encoding/pem"
 	"errors"
 	"fmt"
-	"io"
 	"net/http"
 )
 
@@ -149,8 +148,8 @@ type ChannelRewardRedemptionUpdatedEvent struct {
 	Broadcaster UserEvent `json:"broadcaster"`
 }
 
-// I set it as public to be able to change it in tests.
-// It's not a good practice to do so, but it's the only way to do it for now.
+// DefaultEventPublicKey is the key verifying the signatures of the package functions and of the EventHandlers
+// without a WebhookVerifier. Prefer a WebhookVerifier to changing it.
 var DefaultEventPublicKey = `-----BEGIN PUBLIC KEY-----
 MIIBIjANBgkqhkiG9w0BAQEFAAOCAQ8AMIIBCgKCAQEAq/+l1WnlRrGSolDMA+A8
 6rAhMbQGmQ2SapVcGM3zq8ANXjnhDWocMqfWcTd95btDydITa10kDvHzw9WQOqp2
@@ -161,9 +160,9 @@ BEbZ5jgB8s8ReQV8H+MkuffjdAj3ajDDX3DOJMIut1lBrUVD1AaSrGCKHooWoL2e
 twIDAQAB
 -----END PUBLIC KEY-----`
 
-// I set it to be able to tests without having to sign the events.
-// It's not a good practice to do so, but it's the only way to do it for now.
-// Do not override it in production !
+// SkipSignatureValidation disables the signature verification of the package functions and of the EventHandlers
+// without a WebhookVerifier. It is kept for backward compatibility: prefer a WebhookVerifier with
+// SkipSignatureValidation, which does not affect the rest of the process. Do not override it in production !
 var SkipSignatureValidation = false
 
 // webhookRequestMeta reads Kick webhook headers per https://docs.kick.com/events/event-types.md,
@@ -192,66 +191,22 @@ func webhookRequestMeta(h http.Header) (eventName, version, signature, messageID
 	return eventName, version, signature, messageID, timestamp
 }
 
+// GetEventFromRequest verifies the event of a webhook request with DefaultEventPublicKey, then decodes it into its
+// type. Use a WebhookVerifier to verify events with another key.
 func GetEventFromRequest(request *http.Request) (interface{}, error) {
-	if request == nil {
-		return nil, errors.New("request cannot be nil")
-	}
-
-	eventName, version, eventSignature, messageID, timestamp := webhookRequestMeta(request.Header)
-
-	subscriptionName, err := NewSubscriptionName(eventName)
-	if err != nil {
-		return nil, fmt.Errorf("failed to parse subscription name: %v", err)
-	}
-
-	body, err := io.ReadAll(request.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read body: %v", err)
-	}
-
-	return ValidateAndParseEvent(
-		subscriptionName,
-		version,
-		eventSignature,
-		messageID,
-		timestamp,
-		string(body),
-	)
+	return defaultWebhookVerifier.GetEventFromRequest(request)
 }
 
+// ValidateEvent reports whether the signature of the event is valid for DefaultEventPublicKey.
 func ValidateEvent(
 	header http.Header,
 	body []byte,
 ) bool {
-	if !SkipSignatureValidation {
-		_, _, sig, msgID, ts := webhookRequestMeta(header)
-		payload := struct {
-			signature string
-			messageID string
-			timestamp string
-		}{
-			signature: sig,
-			messageID: msgID,
-			timestamp: ts,
-		}
-
-		signature := bytes.Join([][]byte{
-			[]byte(payload.messageID),
-			[]byte(payload.timestamp),
-			body,
-		}, []byte("."))
-
-		publicKey, _ := parsePublicKey([]byte(DefaultEventPublicKey))
-
-		err := verifyEventValidity(&publicKey, signature, []byte(payload.signature))
-		if err != nil {
-			return false
-		}
-	}
-
-	return true
+	return defaultWebhookVerifier.ValidateEvent(header, body)
 }
 
+// ValidateAndParseEvent verifies the event with DefaultEventPublicKey, then decodes it into the type of
+// subscriptionName and version.
 func ValidateAndParseEvent(
 	subscriptionName SubscriptionName,
 	version string,
@@ -260,20 +215,34 @@ func ValidateAndParseEvent(
 	timestamp string,
 	body string,
 ) (interface{}, error) {
-	if !SkipSignatureValidation {
-		signature := []byte(fmt.Sprintf("%s.%s.%s", messageID, timestamp, body))
+	return defaultWebhookVerifier.ValidateAndParseEvent(subscriptionName, version, eventSignature, messageID, timestamp, body)
+}
 
-		publicKey, err := parsePublicKey([]byte(DefaultEventPublicKey))
-		if err != nil {
-			return nil, fmt.Errorf("failed to parse public key: %v", err)
-		}
+// verifyEventSignature verifies the signature of an event with the key of provider. When the signature does not
+// match, it is verified again with the refreshed key, if it changed.
+func verifyEventSignature(ctx context.Context, provider KeyProvider, eventSignature, messageID, timestamp, body string) error {
+	signature := []byte(fmt.Sprintf("%s.%s.%s", messageID, timestamp, body))
 
-		err = verifyEventValidity(&publicKey, signature, []byte(eventSignature))
-		if err != nil {
-			return nil, fmt.Errorf("failed to verify event validity: %v", err)
+	publicKey, err := provider.PublicKey(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = verifyEventValidity(publicKey, signature, []byte(eventSignature))
+	if errors.Is(err, rsa.ErrVerification) {
+		refreshed, refreshErr := provider.Refresh(ctx)
+		if refreshErr == nil && !refreshed.Equal(publicKey) {
+			err = verifyEventValidity(refreshed, signature, []byte(eventSignature))
 		}
 	}
+	if err != nil {
+		return fmt.Errorf("failed to verify event validity: %w", err)
+	}
+
+	return nil
+}
 
+func parseEvent(subscriptionName SubscriptionName, version string, body string) (interface{}, error) {
 	var event interface{}
 	if versionConstructor, ok := eventConstructors[subscriptionName]; ok {
 		if constructor, ok := versionConstructor[version]; ok {
@@ -283,33 +252,34 @@ func ValidateAndParseEvent(
 
 	err := json.Unmarshal([]byte(body), &event)
 	if err != nil {
-		return nil, fmt.Errorf("failed to unmarshal event: %v", err)
+		return nil, fmt.Errorf("failed to unmarshal event: %w", err)
 	}
 
 	return event, nil
 }
 
-func parsePublicKey(key []byte) (rsa.PublicKey, error) {
+// ParsePublicKey parses a PEM encoded RSA public key, such as the one returned by GetPublicKey.
+func ParsePublicKey(key []byte) (*rsa.PublicKey, error) {
 	block, _ := pem.Decode(key)
 	if block == nil {
-		return rsa.PublicKey{}, errors.New("failed to decode public key")
+		return nil, errors.New("failed to decode public key")
 	}
 
 	if block.Type != "PUBLIC KEY" {
-		return rsa.PublicKey{}, errors.New("not public key")
+		return nil, errors.New("not public key")
 	}
 
 	parsed, err := x509.ParsePKIXPublicKey(block.Bytes)
 	if err != nil {
-		return rsa.PublicKey{}, fmt.Errorf("failed to parse public key: %v", err)
+		return nil, fmt.Errorf("failed to parse public key: %w", err)
 	}
 
 	publicKey, ok := parsed.(*rsa.PublicKey)
 	if !ok {
-		return rsa.PublicKey{}, errors.New("not expected public key interface")
+		return nil, errors.New("not expected public key interface")
 	}
 
-	return *publicKey, nil
+	return publicKey, nil
 }
 
 func verifyEventValidity(publicKey *rsa.PublicKey, body []byte, signature []byte) error {
@@ -317,7 +287,7 @@ func verifyEventValidity(publicKey *rsa.PublicKey, body []byte, signature []byte
 
 	n, err := base64.StdEncoding.Decode(decoded, signature)
 	if err != nil {
-		return fmt.Errorf("failed to decode signature: %v", err)
+		return fmt.Errorf("failed to decode signature: %w", err)
 	}
 
 	signature = decoded[:n]
@@ -325,7 +295,7 @@ func verifyEventValidity(publicKey *rsa.PublicKey, body []byte, signature []byte
 
 	err = rsa.VerifyPKCS1v15(publicKey, crypto.SHA256, hashed[:], signature)
 	if err != nil {
-		return fmt.Errorf("failed to verify signature: %v", err)
+		return fmt.Errorf("failed to verify signature: %w", err)
 	}
 
 	return nil
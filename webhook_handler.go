@@ -0,0 +1,276 @@
+package gokick
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+)
+
+// EventMeta carries the delivery metadata Kick sends in the headers of every webhook request.
+//
+// EventType is the Kick-Event-Type header as sent by Kick. For event types this package does not know,
+// SubscriptionName is not one of the SubscriptionName constants and its String is "unknown".
+type EventMeta struct {
+	SubscriptionName SubscriptionName
+	EventType        string
+	Version          string
+	MessageID        string
+	SubscriptionID   string
+	Timestamp        string
+}
+
+// maxWebhookBodySize is the size of the largest webhook body read by EventHandler, far above the size of Kick
+// events, so that a request cannot make the handler read an unbounded body.
+const maxWebhookBodySize = 1 << 20
+
+type eventHandlerFunc func(ctx context.Context, event interface{}, meta EventMeta) error
+
+// EventHandler is an http.Handler that verifies, parses and routes Kick webhook events to typed callbacks.
+//
+// It answers Kick with 200 once the event has been handled (or when no callback is registered for it, including
+// events of a type or version this package does not know), 400 when the request cannot be parsed, 401 when the
+// signature is invalid, 405 for non-POST requests, 413 when the body is larger than 1 MiB and 500 when a callback
+// returns an error, so Kick retries the delivery.
+//
+// With a WebhookVerifier having a ReplayProtection, it also answers 401 for expired events, and 200 without calling
+// the callbacks for events already received.
+type EventHandler struct {
+	mu          sync.RWMutex
+	handlers    map[SubscriptionName]eventHandlerFunc
+	onUnhandled eventHandlerFunc
+	onError     func(request *http.Request, err error)
+	verifier    *WebhookVerifier
+}
+
+func NewEventHandler() *EventHandler {
+	return &EventHandler{
+		handlers: make(map[SubscriptionName]eventHandlerFunc),
+	}
+}
+
+func registerEventHandler[T any](
+	h *EventHandler,
+	subscriptionName SubscriptionName,
+	callback func(ctx context.Context, event *T, meta EventMeta) error,
+) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if callback == nil {
+		delete(h.handlers, subscriptionName)
+		return
+	}
+
+	h.handlers[subscriptionName] = func(ctx context.Context, event interface{}, meta EventMeta) error {
+		typed, ok := event.(*T)
+		if !ok {
+			return fmt.Errorf("unexpected event type %T for %s version %s", event, subscriptionName, meta.Version)
+		}
+
+		return callback(ctx, typed, meta)
+	}
+}
+
+func (h *EventHandler) OnChatMessage(callback func(ctx context.Context, event *ChatMessageEvent, meta EventMeta) error) {
+	registerEventHandler(h, SubscriptionNameChatMessage, callback)
+}
+
+func (h *EventHandler) OnChannelFollow(callback func(ctx context.Context, event *ChannelFollowEvent, meta EventMeta) error) {
+	registerEventHandler(h, SubscriptionNameChannelFollow, callback)
+}
+
+func (h *EventHandler) OnChannelSubscriptionRenewal(
+	callback func(ctx context.Context, event *ChannelSubscriptionRenewalEvent, meta EventMeta) error,
+) {
+	registerEventHandler(h, SubscriptionNameChannelSubscriptionRenewal, callback)
+}
+
+func (h *EventHandler) OnChannelSubscriptionGifts(
+	callback func(ctx context.Context, event *ChannelSubscriptionGiftsEvent, meta EventMeta) error,
+) {
+	registerEventHandler(h, SubscriptionNameChannelSubscriptionGifts, callback)
+}
+
+func (h *EventHandler) OnChannelSubscriptionCreated(
+	callback func(ctx context.Context, event *ChannelSubscriptionCreatedEvent, meta EventMeta) error,
+) {
+	registerEventHandler(h, SubscriptionNameChannelSubscriptionCreated, callback)
+}
+
+func (h *EventHandler) OnLivestreamStatusUpdated(
+	callback func(ctx context.Context, event *LivestreamStatusUpdatedEvent, meta EventMeta) error,
+) {
+	registerEventHandler(h, SubscriptionNameLivestreamStatusUpdated, callback)
+}
+
+func (h *EventHandler) OnLivestreamMetadataUpdated(
+	callback func(ctx context.Context, event *LivestreamMetadataUpdatedEvent, meta EventMeta) error,
+) {
+	registerEventHandler(h, SubscriptionNameLivestreamMetadataUpdated, callback)
+}
+
+func (h *EventHandler) OnModerationBanned(callback func(ctx context.Context, event *ModerationBannedEvent, meta EventMeta) error) {
+	registerEventHandler(h, SubscriptionNameModerationBanned, callback)
+}
+
+func (h *EventHandler) OnKicksGifted(callback func(ctx context.Context, event *KicksGiftedEvent, meta EventMeta) error) {
+	registerEventHandler(h, SubscriptionNameKicksGifted, callback)
+}
+
+func (h *EventHandler) OnChannelRewardRedemptionUpdated(
+	callback func(ctx context.Context, event *ChannelRewardRedemptionUpdatedEvent, meta EventMeta) error,
+) {
+	registerEventHandler(h, SubscriptionNameChannelRewardRedemptionUpdated, callback)
+}
+
+// OnUnhandled registers a fallback callback for events without a typed callback,
+// including event types and versions this package does not know how to decode.
+func (h *EventHandler) OnUnhandled(callback func(ctx context.Context, event interface{}, meta EventMeta) error) {
+	h.mu.Lock()
+	h.onUnhandled = callback
+	h.mu.Unlock()
+}
+
+// OnError registers a callback invoked whenever a request is rejected or a callback fails.
+func (h *EventHandler) OnError(callback func(request *http.Request, err error)) {
+	h.mu.Lock()
+	h.onError = callback
+	h.mu.Unlock()
+}
+
+// SetVerifier makes the handler verify events with verifier, its key and replay protection, or with
+// DefaultEventPublicKey and SkipSignatureValidation when verifier is nil. With a replay protection, the message ID
+// of an event whose callback fails is forgotten, so that the retry of Kick is handled.
+func (h *EventHandler) SetVerifier(verifier *WebhookVerifier) {
+	h.mu.Lock()
+	h.verifier = verifier
+	h.mu.Unlock()
+}
+
+func (h *EventHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		h.reject(w, r, http.StatusMethodNotAllowed, fmt.Errorf("unexpected method %s", r.Method))
+		return
+	}
+
+	eventName, version, signature, messageID, timestamp := webhookRequestMeta(r.Header)
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxWebhookBodySize))
+	if err != nil {
+		statusCode := http.StatusBadRequest
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			statusCode = http.StatusRequestEntityTooLarge
+		}
+		h.reject(w, r, statusCode, fmt.Errorf("failed to read body: %w", err))
+		return
+	}
+
+	h.mu.RLock()
+	verifier := h.verifier
+	h.mu.RUnlock()
+	if verifier == nil {
+		verifier = defaultWebhookVerifier
+	}
+
+	trace := ContextTrace(r.Context())
+
+	err = trace.verifyEventSignature(r.Context(), verifier, signature, messageID, timestamp, string(body))
+	if err != nil {
+		h.reject(w, r, http.StatusUnauthorized, err)
+		return
+	}
+
+	err = verifier.checkReplay(r.Context(), messageID, timestamp)
+	if err != nil {
+		h.rejectReplay(w, r, err)
+		return
+	}
+
+	subscriptionName, err := NewSubscriptionName(eventName)
+	if err != nil {
+		subscriptionName = SubscriptionName(-1)
+	}
+
+	event, err := trace.parseEvent(subscriptionName, version, string(body))
+	if err != nil {
+		verifier.forget(r.Context(), messageID)
+		h.reject(w, r, http.StatusBadRequest, err)
+		return
+	}
+
+	meta := EventMeta{
+		SubscriptionName: subscriptionName,
+		EventType:        eventName,
+		Version:          version,
+		MessageID:        messageID,
+		SubscriptionID:   r.Header.Get("Kick-Event-Subscription-Id"),
+		Timestamp:        timestamp,
+	}
+
+	err = h.dispatch(trace.dispatchStart(r.Context(), meta), event, meta)
+	trace.dispatchDone(err)
+	if err != nil {
+		verifier.forget(r.Context(), messageID)
+		h.reject(w, r, http.StatusInternalServerError, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func (h *EventHandler) dispatch(ctx context.Context, event interface{}, meta EventMeta) error {
+	h.mu.RLock()
+	handler, ok := h.handlers[meta.SubscriptionName]
+	onUnhandled := h.onUnhandled
+	h.mu.RUnlock()
+
+	if ok && eventHasKnownType(meta.SubscriptionName, meta.Version) {
+		return handler(ctx, event, meta)
+	}
+
+	if onUnhandled != nil {
+		return onUnhandled(ctx, event, meta)
+	}
+
+	return nil
+}
+
+func (h *EventHandler) reject(w http.ResponseWriter, r *http.Request, statusCode int, err error) {
+	h.mu.RLock()
+	onError := h.onError
+	h.mu.RUnlock()
+
+	if onError != nil {
+		onError(r, err)
+	}
+
+	http.Error(w, http.StatusText(statusCode), statusCode)
+}
+
+// rejectReplay answers an event refused by the replay protection. Duplicates are acknowledged with 200, as the
+// event has already been received, so that Kick stops delivering it.
+func (h *EventHandler) rejectReplay(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case errors.Is(err, ErrDuplicateEvent):
+		h.reject(w, r, http.StatusOK, err)
+	case errors.Is(err, ErrEventExpired):
+		h.reject(w, r, http.StatusUnauthorized, err)
+	default:
+		h.reject(w, r, http.StatusInternalServerError, err)
+	}
+}
+
+func eventHasKnownType(subscriptionName SubscriptionName, version string) bool {
+	versionConstructor, ok := eventConstructors[subscriptionName]
+	if !ok {
+		return false
+	}
+
+	_, ok = versionConstructor[version]
+
+	return ok
+}
@@ -0,0 +1,382 @@
+package gokick_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/scorfly/gokick"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newWebhookRequest(t *testing.T, eventType, version, body string) *http.Request {
+	t.Helper()
+
+	req := httptest.NewRequest(http.MethodPost, "https://domain.tld/webhook", strings.NewReader(body))
+	req.Header.Set("Kick-Event-Type", eventType)
+	req.Header.Set("Kick-Event-Version", version)
+	req.Header.Set("Kick-Event-Signature", "signature")
+	req.Header.Set("Kick-Event-Message-Id", "01JMND5PSxxxxxx")
+	req.Header.Set("Kick-Event-Subscription-Id", "01JMN13xxxxxx")
+	req.Header.Set("Kick-Event-Message-Timestamp", "2025-02-21T23:23:36Z")
+
+	return req
+}
+
+func TestEventHandlerError(t *testing.T) {
+	t.Run("method not allowed", func(t *testing.T) {
+		var handlerErr error
+		handler := gokick.NewEventHandler()
+		handler.OnError(func(_ *http.Request, err error) { handlerErr = err })
+
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "https://domain.tld/webhook", http.NoBody))
+
+		assert.Equal(t, http.StatusMethodNotAllowed, recorder.Code)
+		require.EqualError(t, handlerErr, "unexpected method GET")
+	})
+
+	t.Run("unknown event type with invalid signature", func(t *testing.T) {
+		handler := gokick.NewEventHandler()
+		handler.OnUnhandled(func(context.Context, interface{}, gokick.EventMeta) error {
+			t.Fatal("callback must not be called")
+			return nil
+		})
+
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, newWebhookRequest(t, "invalid", "1", "{}"))
+
+		assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+	})
+
+	t.Run("invalid body", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "https://domain.tld/webhook", faultyReader{})
+		req.Header.Set("Kick-Event-Type", "chat.message.sent")
+
+		recorder := httptest.NewRecorder()
+		gokick.NewEventHandler().ServeHTTP(recorder, req)
+
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	})
+
+	t.Run("body too large", func(t *testing.T) {
+		skipSignatureValidation(t)
+
+		var handlerErr error
+		handler := gokick.NewEventHandler()
+		handler.OnError(func(_ *http.Request, err error) { handlerErr = err })
+
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, newWebhookRequest(t, "chat.message.sent", "1", `"`+strings.Repeat("a", 1<<20)+`"`))
+
+		assert.Equal(t, http.StatusRequestEntityTooLarge, recorder.Code)
+		require.EqualError(t, handlerErr, "failed to read body: http: request body too large")
+	})
+
+	t.Run("invalid signature", func(t *testing.T) {
+		var handlerErr error
+		handler := gokick.NewEventHandler()
+		handler.OnError(func(_ *http.Request, err error) { handlerErr = err })
+		handler.OnChatMessage(func(context.Context, *gokick.ChatMessageEvent, gokick.EventMeta) error {
+			t.Fatal("callback must not be called")
+			return nil
+		})
+
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, newWebhookRequest(t, "chat.message.sent", "1", "{}"))
+
+		assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+		require.EqualError(t, handlerErr, "failed to verify event validity: failed to decode signature: illegal base64 data at input byte 8")
+	})
+
+	t.Run("invalid JSON", func(t *testing.T) {
+		skipSignatureValidation(t)
+
+		recorder := httptest.NewRecorder()
+		gokick.NewEventHandler().ServeHTTP(recorder, newWebhookRequest(t, "chat.message.sent", "1", "not json"))
+
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+	})
+
+	t.Run("callback error", func(t *testing.T) {
+		skipSignatureValidation(t)
+
+		var handlerErr error
+		handler := gokick.NewEventHandler()
+		handler.OnError(func(_ *http.Request, err error) { handlerErr = err })
+		handler.OnChannelFollow(func(context.Context, *gokick.ChannelFollowEvent, gokick.EventMeta) error {
+			return errors.New("storage unavailable")
+		})
+
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, newWebhookRequest(t, "channel.followed", "1", "{}"))
+
+		assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+		require.EqualError(t, handlerErr, "storage unavailable")
+	})
+}
+
+func TestEventHandlerSuccess(t *testing.T) {
+	skipSignatureValidation(t)
+
+	called := make(map[gokick.SubscriptionName]bool)
+	record := func(meta gokick.EventMeta) error {
+		called[meta.SubscriptionName] = true
+		return nil
+	}
+
+	handler := gokick.NewEventHandler()
+	handler.OnChatMessage(func(_ context.Context, event *gokick.ChatMessageEvent, meta gokick.EventMeta) error {
+		assert.Equal(t, "hello", event.Content)
+		assert.Equal(t, "01JMND5PSxxxxxx", meta.MessageID)
+		assert.Equal(t, "01JMN13xxxxxx", meta.SubscriptionID)
+		assert.Equal(t, "2025-02-21T23:23:36Z", meta.Timestamp)
+		assert.Equal(t, "1", meta.Version)
+		return record(meta)
+	})
+	handler.OnChannelFollow(func(_ context.Context, _ *gokick.ChannelFollowEvent, meta gokick.EventMeta) error {
+		return record(meta)
+	})
+	handler.OnChannelSubscriptionRenewal(func(_ context.Context, _ *gokick.ChannelSubscriptionRenewalEvent, meta gokick.EventMeta) error {
+		return record(meta)
+	})
+	handler.OnChannelSubscriptionGifts(func(_ context.Context, _ *gokick.ChannelSubscriptionGiftsEvent, meta gokick.EventMeta) error {
+		return record(meta)
+	})
+	handler.OnChannelSubscriptionCreated(func(_ context.Context, _ *gokick.ChannelSubscriptionCreatedEvent, meta gokick.EventMeta) error {
+		return record(meta)
+	})
+	handler.OnLivestreamStatusUpdated(func(_ context.Context, _ *gokick.LivestreamStatusUpdatedEvent, meta gokick.EventMeta) error {
+		return record(meta)
+	})
+	handler.OnLivestreamMetadataUpdated(func(_ context.Context, _ *gokick.LivestreamMetadataUpdatedEvent, meta gokick.EventMeta) error {
+		return record(meta)
+	})
+	handler.OnModerationBanned(func(_ context.Context, _ *gokick.ModerationBannedEvent, meta gokick.EventMeta) error {
+		return record(meta)
+	})
+	handler.OnKicksGifted(func(_ context.Context, _ *gokick.KicksGiftedEvent, meta gokick.EventMeta) error {
+		return record(meta)
+	})
+	handler.OnChannelRewardRedemptionUpdated(
+		func(_ context.Context, _ *gokick.ChannelRewardRedemptionUpdatedEvent, meta gokick.EventMeta) error {
+			return record(meta)
+		},
+	)
+
+	tests := []struct {
+		eventType string
+		body      string
+		want      gokick.SubscriptionName
+	}{
+		{"chat.message.sent", `{"content":"hello"}`, gokick.SubscriptionNameChatMessage},
+		{"channel.followed", "{}", gokick.SubscriptionNameChannelFollow},
+		{"channel.subscription.renewal", "{}", gokick.SubscriptionNameChannelSubscriptionRenewal},
+		{"channel.subscription.gifts", "{}", gokick.SubscriptionNameChannelSubscriptionGifts},
+		{"channel.subscription.new", "{}", gokick.SubscriptionNameChannelSubscriptionCreated},
+		{"livestream.status.updated", "{}", gokick.SubscriptionNameLivestreamStatusUpdated},
+		{"livestream.metadata.updated", "{}", gokick.SubscriptionNameLivestreamMetadataUpdated},
+		{"moderation.banned", "{}", gokick.SubscriptionNameModerationBanned},
+		{"kicks.gifted", "{}", gokick.SubscriptionNameKicksGifted},
+		{"channel.reward.redemption.updated", "{}", gokick.SubscriptionNameChannelRewardRedemptionUpdated},
+	}
+	for _, tt := range tests {
+		t.Run(tt.eventType, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, newWebhookRequest(t, tt.eventType, "1", tt.body))
+
+			assert.Equal(t, http.StatusOK, recorder.Code)
+			assert.True(t, called[tt.want])
+		})
+	}
+}
+
+func TestEventHandlerUnhandled(t *testing.T) {
+	skipSignatureValidation(t)
+
+	t.Run("no callback acknowledges the event", func(t *testing.T) {
+		recorder := httptest.NewRecorder()
+		gokick.NewEventHandler().ServeHTTP(recorder, newWebhookRequest(t, "chat.message.sent", "1", "{}"))
+
+		assert.Equal(t, http.StatusOK, recorder.Code)
+	})
+
+	t.Run("unknown version goes to fallback", func(t *testing.T) {
+		var received interface{}
+		handler := gokick.NewEventHandler()
+		handler.OnChatMessage(func(context.Context, *gokick.ChatMessageEvent, gokick.EventMeta) error {
+			t.Fatal("typed callback must not be called")
+			return nil
+		})
+		handler.OnUnhandled(func(_ context.Context, event interface{}, _ gokick.EventMeta) error {
+			received = event
+			return nil
+		})
+
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, newWebhookRequest(t, "chat.message.sent", "2", `{"content":"hello"}`))
+
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.Equal(t, map[string]interface{}{"content": "hello"}, received)
+	})
+
+	t.Run("unknown event type goes to fallback", func(t *testing.T) {
+		var received interface{}
+		var receivedMeta gokick.EventMeta
+		handler := gokick.NewEventHandler()
+		handler.OnChatMessage(func(context.Context, *gokick.ChatMessageEvent, gokick.EventMeta) error {
+			t.Fatal("typed callback must not be called")
+			return nil
+		})
+		handler.OnUnhandled(func(_ context.Context, event interface{}, meta gokick.EventMeta) error {
+			received = event
+			receivedMeta = meta
+			return nil
+		})
+
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, newWebhookRequest(t, "channel.raided", "1", `{"viewers":12}`))
+
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.Equal(t, map[string]interface{}{"viewers": float64(12)}, received)
+		assert.Equal(t, "channel.raided", receivedMeta.EventType)
+		assert.Equal(t, "unknown", receivedMeta.SubscriptionName.String())
+	})
+
+	t.Run("unknown event type without fallback is acknowledged", func(t *testing.T) {
+		recorder := httptest.NewRecorder()
+		gokick.NewEventHandler().ServeHTTP(recorder, newWebhookRequest(t, "channel.raided", "1", "{}"))
+
+		assert.Equal(t, http.StatusOK, recorder.Code)
+	})
+
+	t.Run("nil callback unregisters", func(t *testing.T) {
+		handler := gokick.NewEventHandler()
+		handler.OnChatMessage(func(context.Context, *gokick.ChatMessageEvent, gokick.EventMeta) error {
+			return errors.New("should be unregistered")
+		})
+		handler.OnChatMessage(nil)
+
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, newWebhookRequest(t, "chat.message.sent", "1", "{}"))
+
+		assert.Equal(t, http.StatusOK, recorder.Code)
+	})
+}
+
+func TestEventHandlerReplayProtection(t *testing.T) {
+	newVerifier := func(t *testing.T, store gokick.SeenStore) *gokick.WebhookVerifier {
+		return newTestVerifier(t, gokick.WebhookVerifierOptions{
+			SkipSignatureValidation: true,
+			ReplayProtection:        gokick.NewReplayProtection(gokick.ReplayProtectionOptions{Store: store}),
+		})
+	}
+
+	newRequest := func(t *testing.T, timestamp string) *http.Request {
+		req := newWebhookRequest(t, "channel.followed", "1", "{}")
+		req.Header.Set("Kick-Event-Message-Timestamp", timestamp)
+		return req
+	}
+
+	t.Run("duplicate event is acknowledged once", func(t *testing.T) {
+		calls := 0
+		var handlerErr error
+		handler := gokick.NewEventHandler()
+		handler.SetVerifier(newVerifier(t, nil))
+		handler.OnError(func(_ *http.Request, err error) { handlerErr = err })
+		handler.OnChannelFollow(func(context.Context, *gokick.ChannelFollowEvent, gokick.EventMeta) error {
+			calls++
+			return nil
+		})
+
+		timestamp := time.Now().Format(time.RFC3339)
+		for range 2 {
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, newRequest(t, timestamp))
+			assert.Equal(t, http.StatusOK, recorder.Code)
+		}
+
+		assert.Equal(t, 1, calls)
+		require.ErrorIs(t, handlerErr, gokick.ErrDuplicateEvent)
+	})
+
+	t.Run("expired event", func(t *testing.T) {
+		var handlerErr error
+		handler := gokick.NewEventHandler()
+		handler.SetVerifier(newVerifier(t, nil))
+		handler.OnError(func(_ *http.Request, err error) { handlerErr = err })
+		handler.OnChannelFollow(func(context.Context, *gokick.ChannelFollowEvent, gokick.EventMeta) error {
+			t.Fatal("callback must not be called")
+			return nil
+		})
+
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, newRequest(t, "2025-02-21T23:23:36Z"))
+
+		assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+		require.ErrorIs(t, handlerErr, gokick.ErrEventExpired)
+	})
+
+	t.Run("store error", func(t *testing.T) {
+		handler := gokick.NewEventHandler()
+		handler.SetVerifier(newVerifier(t, failingSeenStore{}))
+
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, newRequest(t, time.Now().Format(time.RFC3339)))
+
+		assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+	})
+
+	t.Run("failed event is handled again on retry", func(t *testing.T) {
+		calls := 0
+		handler := gokick.NewEventHandler()
+		handler.SetVerifier(newVerifier(t, nil))
+		handler.OnChannelFollow(func(context.Context, *gokick.ChannelFollowEvent, gokick.EventMeta) error {
+			calls++
+			if calls == 1 {
+				return errors.New("storage unavailable")
+			}
+			return nil
+		})
+
+		timestamp := time.Now().Format(time.RFC3339)
+
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, newRequest(t, timestamp))
+		assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+
+		recorder = httptest.NewRecorder()
+		handler.ServeHTTP(recorder, newRequest(t, timestamp))
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.Equal(t, 2, calls)
+	})
+
+	t.Run("unparsable event is parsed again on retry", func(t *testing.T) {
+		handler := gokick.NewEventHandler()
+		handler.SetVerifier(newVerifier(t, nil))
+
+		timestamp := time.Now().Format(time.RFC3339)
+		for range 2 {
+			req := newWebhookRequest(t, "channel.followed", "1", "{")
+			req.Header.Set("Kick-Event-Message-Timestamp", timestamp)
+
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, req)
+			assert.Equal(t, http.StatusBadRequest, recorder.Code, "the event is not reported as a duplicate")
+		}
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		handler := gokick.NewEventHandler()
+		handler.SetVerifier(newTestVerifier(t, gokick.WebhookVerifierOptions{SkipSignatureValidation: true}))
+
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, newRequest(t, "2025-02-21T23:23:36Z"))
+		assert.Equal(t, http.StatusOK, recorder.Code)
+	})
+}
@@ -0,0 +1,160 @@
+package gokick
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+const (
+	defaultReplayMaxAge        = 10 * time.Minute
+	memorySeenStorePrunePeriod = time.Minute
+)
+
+var (
+	// ErrEventExpired is returned when the timestamp of a webhook event is further than the maximum age from now.
+	ErrEventExpired = errors.New("gokick: webhook event expired")
+	// ErrDuplicateEvent is returned when the message ID of a webhook event was already seen.
+	ErrDuplicateEvent = errors.New("gokick: duplicate webhook event")
+)
+
+// SeenStore records the message IDs of the webhook events already received. Implementations must be safe for
+// concurrent use, and MarkSeen must be atomic so that concurrent deliveries of an event are not both accepted.
+type SeenStore interface {
+	// MarkSeen records messageID for ttl and returns whether it was already recorded.
+	MarkSeen(ctx context.Context, messageID string, ttl time.Duration) (bool, error)
+	// Forget removes messageID, so that a new delivery of the event is accepted.
+	Forget(ctx context.Context, messageID string) error
+}
+
+// ReplayProtectionOptions configures a ReplayProtection.
+type ReplayProtectionOptions struct {
+	// MaxAge is how far the Kick-Event-Message-Timestamp of an event may be from now. Defaults to 10 minutes.
+	MaxAge time.Duration
+	// Store records the message IDs seen within MaxAge. Defaults to a MemorySeenStore.
+	Store SeenStore
+}
+
+// ReplayProtection rejects the webhook events whose timestamp is outside a time window, and the events whose
+// message ID was already seen within that window. Together with the signature, which covers both the message ID
+// and the timestamp, it prevents a captured event from being replayed, and an event retried by Kick from being
+// processed twice.
+type ReplayProtection struct {
+	maxAge time.Duration
+	store  SeenStore
+	now    func() time.Time
+}
+
+func NewReplayProtection(options ReplayProtectionOptions) *ReplayProtection {
+	if options.MaxAge <= 0 {
+		options.MaxAge = defaultReplayMaxAge
+	}
+	if options.Store == nil {
+		options.Store = NewMemorySeenStore()
+	}
+
+	return &ReplayProtection{
+		maxAge: options.MaxAge,
+		store:  options.Store,
+		now:    time.Now,
+	}
+}
+
+// Check returns an error matching ErrEventExpired when timestamp, an RFC 3339 date, is more than the maximum age
+// away from now, and an error matching ErrDuplicateEvent when messageID was already checked within the maximum
+// age. Otherwise messageID is recorded as seen. Check must be called once the signature has been verified.
+func (p *ReplayProtection) Check(ctx context.Context, messageID, timestamp string) error {
+	err := p.checkAge(timestamp)
+	if err != nil {
+		return err
+	}
+
+	if messageID == "" {
+		return errors.New("missing event message ID")
+	}
+
+	seen, err := p.store.MarkSeen(ctx, messageID, p.maxAge)
+	if err != nil {
+		return fmt.Errorf("failed to mark event as seen: %w", err)
+	}
+	if seen {
+		return fmt.Errorf("%w: message ID %s", ErrDuplicateEvent, messageID)
+	}
+
+	return nil
+}
+
+// checkAge returns an error matching ErrEventExpired when timestamp is more than the maximum age away from now.
+func (p *ReplayProtection) checkAge(timestamp string) error {
+	sentAt, err := time.Parse(time.RFC3339, timestamp)
+	if err != nil {
+		return fmt.Errorf("failed to parse event timestamp: %w", err)
+	}
+
+	age := p.now().Sub(sentAt)
+	if age > p.maxAge || age < -p.maxAge {
+		return fmt.Errorf("%w: sent at %s", ErrEventExpired, timestamp)
+	}
+
+	return nil
+}
+
+// Forget removes messageID from the seen events, so that Kick can deliver the event again. It is meant for events
+// that could not be processed.
+func (p *ReplayProtection) Forget(ctx context.Context, messageID string) error {
+	err := p.store.Forget(ctx, messageID)
+	if err != nil {
+		return fmt.Errorf("failed to forget event: %w", err)
+	}
+
+	return nil
+}
+
+// MemorySeenStore is an in-memory SeenStore, dropping message IDs once their TTL is over. It is local to the
+// process: use a shared store when several instances receive the webhooks.
+type MemorySeenStore struct {
+	mu      sync.Mutex
+	entries map[string]time.Time
+	pruneAt time.Time
+	now     func() time.Time
+}
+
+func NewMemorySeenStore() *MemorySeenStore {
+	return &MemorySeenStore{
+		entries: make(map[string]time.Time),
+		now:     time.Now,
+	}
+}
+
+func (s *MemorySeenStore) MarkSeen(_ context.Context, messageID string, ttl time.Duration) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	if !now.Before(s.pruneAt) {
+		for id, expiresAt := range s.entries {
+			if !now.Before(expiresAt) {
+				delete(s.entries, id)
+			}
+		}
+		s.pruneAt = now.Add(memorySeenStorePrunePeriod)
+	}
+
+	expiresAt, seen := s.entries[messageID]
+	seen = seen && now.Before(expiresAt)
+	if !seen {
+		s.entries[messageID] = now.Add(ttl)
+	}
+
+	return seen, nil
+}
+
+func (s *MemorySeenStore) Forget(_ context.Context, messageID string) error {
+	s.mu.Lock()
+	delete(s.entries, messageID)
+	s.mu.Unlock()
+
+	return nil
+}
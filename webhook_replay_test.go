@@ -0,0 +1,154 @@
+package gokick_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/scorfly/gokick"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type failingSeenStore struct{}
+
+func (failingSeenStore) MarkSeen(context.Context, string, time.Duration) (bool, error) {
+	return false, errors.New("store unavailable")
+}
+
+func (failingSeenStore) Forget(context.Context, string) error {
+	return errors.New("store unavailable")
+}
+
+func TestReplayProtectionCheck(t *testing.T) {
+	now := time.Now().UTC()
+
+	tests := []struct {
+		name      string
+		messageID string
+		timestamp string
+		wantErr   error
+		errString string
+	}{
+		{
+			name:      "recent event",
+			messageID: "message-1",
+			timestamp: now.Add(-time.Minute).Format(time.RFC3339),
+		},
+		{
+			name:      "fractional seconds",
+			messageID: "message-2",
+			timestamp: now.Format(time.RFC3339Nano),
+		},
+		{
+			name:      "expired event",
+			messageID: "message-3",
+			timestamp: "2025-02-21T23:23:36Z",
+			wantErr:   gokick.ErrEventExpired,
+			errString: "gokick: webhook event expired: sent at 2025-02-21T23:23:36Z",
+		},
+		{
+			name:      "event from the future",
+			messageID: "message-4",
+			timestamp: now.Add(time.Hour).Format(time.RFC3339),
+			wantErr:   gokick.ErrEventExpired,
+		},
+		{
+			name:      "invalid timestamp",
+			messageID: "message-5",
+			timestamp: "yesterday",
+			errString: `failed to parse event timestamp: parsing time "yesterday" as "2006-01-02T15:04:05Z07:00": ` +
+				`cannot parse "yesterday" as "2006"`,
+		},
+		{
+			name:      "missing message ID",
+			timestamp: now.Format(time.RFC3339),
+			errString: "missing event message ID",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			protection := gokick.NewReplayProtection(gokick.ReplayProtectionOptions{})
+
+			err := protection.Check(context.Background(), tt.messageID, tt.timestamp)
+			if tt.wantErr == nil && tt.errString == "" {
+				require.NoError(t, err)
+				return
+			}
+
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+			}
+			if tt.errString != "" {
+				require.EqualError(t, err, tt.errString)
+			}
+		})
+	}
+}
+
+func TestReplayProtectionDuplicate(t *testing.T) {
+	protection := gokick.NewReplayProtection(gokick.ReplayProtectionOptions{MaxAge: time.Minute})
+	timestamp := time.Now().Format(time.RFC3339)
+
+	require.NoError(t, protection.Check(context.Background(), "message-1", timestamp))
+	require.NoError(t, protection.Check(context.Background(), "message-2", timestamp))
+
+	err := protection.Check(context.Background(), "message-1", timestamp)
+	require.ErrorIs(t, err, gokick.ErrDuplicateEvent)
+	require.EqualError(t, err, "gokick: duplicate webhook event: message ID message-1")
+
+	require.NoError(t, protection.Forget(context.Background(), "message-1"))
+	require.NoError(t, protection.Check(context.Background(), "message-1", timestamp), "a forgotten event is accepted again")
+}
+
+func TestReplayProtectionSeenExpiry(t *testing.T) {
+	sentAt := time.Now()
+	now := sentAt.Add(-50 * time.Second)
+	protection := gokick.NewReplayProtection(gokick.ReplayProtectionOptions{MaxAge: time.Minute})
+	gokick.SetReplayProtectionNow(protection, func() time.Time { return now })
+	timestamp := sentAt.Format(time.RFC3339)
+
+	require.NoError(t, protection.Check(context.Background(), "message-1", timestamp))
+	now = sentAt.Add(5 * time.Second)
+	require.ErrorIs(t, protection.Check(context.Background(), "message-1", timestamp), gokick.ErrDuplicateEvent)
+
+	now = sentAt.Add(20 * time.Second)
+	require.NoError(t, protection.Check(context.Background(), "message-1", timestamp), "the message ID expired after MaxAge")
+
+	now = sentAt.Add(2 * time.Minute)
+	require.ErrorIs(t, protection.Check(context.Background(), "message-1", timestamp), gokick.ErrEventExpired)
+}
+
+func TestReplayProtectionStoreError(t *testing.T) {
+	protection := gokick.NewReplayProtection(gokick.ReplayProtectionOptions{Store: failingSeenStore{}})
+
+	err := protection.Check(context.Background(), "message-1", time.Now().Format(time.RFC3339))
+	require.EqualError(t, err, "failed to mark event as seen: store unavailable")
+
+	err = protection.Forget(context.Background(), "message-1")
+	require.EqualError(t, err, "failed to forget event: store unavailable")
+}
+
+func TestMemorySeenStore(t *testing.T) {
+	store := gokick.NewMemorySeenStore()
+
+	seen, err := store.MarkSeen(context.Background(), "message-1", time.Minute)
+	require.NoError(t, err)
+	assert.False(t, seen)
+
+	seen, err = store.MarkSeen(context.Background(), "message-1", time.Minute)
+	require.NoError(t, err)
+	assert.True(t, seen)
+
+	for range 2 {
+		seen, err = store.MarkSeen(context.Background(), "message-2", 0)
+		require.NoError(t, err)
+		assert.False(t, seen, "the message ID expires right away")
+	}
+
+	require.NoError(t, store.Forget(context.Background(), "message-1"))
+	seen, err = store.MarkSeen(context.Background(), "message-1", time.Minute)
+	require.NoError(t, err)
+	assert.False(t, seen)
+}
@@ -0,0 +1,162 @@
+package gokick
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"reflect"
+	"time"
+)
+
+const webhookSignerKeyBits = 2048
+
+// WebhookDelivery is the metadata of a webhook event sent by a WebhookSigner. Empty fields but SubscriptionName are
+// generated.
+type WebhookDelivery struct {
+	SubscriptionName SubscriptionName
+	// Version defaults to "1".
+	Version string
+	// MessageID defaults to a random identifier. Reuse one to simulate a retry of Kick.
+	MessageID string
+	// SubscriptionID defaults to a random identifier.
+	SubscriptionID string
+	// Timestamp defaults to now.
+	Timestamp time.Time
+}
+
+// WebhookSigner produces webhook requests signed like Kick does, to test webhook handlers or simulate Kick
+// locally. Verify them with a WebhookVerifier using the KeyProvider of the signer.
+type WebhookSigner struct {
+	privateKey *rsa.PrivateKey
+}
+
+func NewWebhookSigner(privateKey *rsa.PrivateKey) *WebhookSigner {
+	return &WebhookSigner{
+		privateKey: privateKey,
+	}
+}
+
+// GenerateWebhookSigner returns a WebhookSigner with a new 2048 bits key.
+func GenerateWebhookSigner() (*WebhookSigner, error) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, webhookSignerKeyBits)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate private key: %w", err)
+	}
+
+	return NewWebhookSigner(privateKey), nil
+}
+
+// PublicKey returns the key verifying the signatures of s.
+func (s *WebhookSigner) PublicKey() *rsa.PublicKey {
+	return &s.privateKey.PublicKey
+}
+
+// PublicKeyPEM returns the PEM encoding of the key verifying the signatures of s, as returned by GetPublicKey.
+func (s *WebhookSigner) PublicKeyPEM() ([]byte, error) {
+	der, err := x509.MarshalPKIXPublicKey(s.PublicKey())
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal public key: %w", err)
+	}
+
+	return pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), nil
+}
+
+// KeyProvider returns a KeyProvider of the key verifying the signatures of s, for a WebhookVerifier.
+func (s *WebhookSigner) KeyProvider() *StaticKeyProvider {
+	return NewStaticKeyProvider(s.PublicKey())
+}
+
+// Sign returns the base64 encoded signature of an event, as sent in the Kick-Event-Signature header.
+func (s *WebhookSigner) Sign(messageID, timestamp string, body []byte) (string, error) {
+	hashed := sha256.Sum256([]byte(fmt.Sprintf("%s.%s.%s", messageID, timestamp, body)))
+
+	signature, err := rsa.SignPKCS1v15(rand.Reader, s.privateKey, crypto.SHA256, hashed[:])
+	if err != nil {
+		return "", fmt.Errorf("failed to sign event: %w", err)
+	}
+
+	return base64.StdEncoding.EncodeToString(signature), nil
+}
+
+// NewRequest returns a signed POST request to url delivering event, such as a *ChatMessageEvent. The subscription
+// name is the one of the event type, with version 1.
+func (s *WebhookSigner) NewRequest(ctx context.Context, url string, event interface{}) (*http.Request, error) {
+	subscriptionName, ok := eventSubscriptionName(event)
+	if !ok {
+		return nil, fmt.Errorf("unsupported event type %T", event)
+	}
+
+	body, err := json.Marshal(event)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal event: %w", err)
+	}
+
+	return s.NewRawRequest(ctx, url, WebhookDelivery{SubscriptionName: subscriptionName}, body)
+}
+
+// NewRawRequest returns a signed POST request to url delivering body, with the metadata of delivery.
+func (s *WebhookSigner) NewRawRequest(ctx context.Context, url string, delivery WebhookDelivery, body []byte) (*http.Request, error) {
+	if delivery.Version == "" {
+		delivery.Version = "1"
+	}
+	if delivery.MessageID == "" {
+		delivery.MessageID = rand.Text()
+	}
+	if delivery.SubscriptionID == "" {
+		delivery.SubscriptionID = rand.Text()
+	}
+	if delivery.Timestamp.IsZero() {
+		delivery.Timestamp = time.Now()
+	}
+	timestamp := delivery.Timestamp.UTC().Format(time.RFC3339)
+
+	signature, err := s.Sign(delivery.MessageID, timestamp, body)
+	if err != nil {
+		return nil, err
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("Kick-Event-Type", delivery.SubscriptionName.String())
+	request.Header.Set("Kick-Event-Version", delivery.Version)
+	request.Header.Set("Kick-Event-Message-Id", delivery.MessageID)
+	request.Header.Set("Kick-Event-Subscription-Id", delivery.SubscriptionID)
+	request.Header.Set("Kick-Event-Message-Timestamp", timestamp)
+	request.Header.Set("Kick-Event-Signature", signature)
+
+	return request, nil
+}
+
+// eventSubscriptionName returns the subscription name of an event type, given as a value or a pointer.
+func eventSubscriptionName(event interface{}) (SubscriptionName, bool) {
+	eventType := reflect.TypeOf(event)
+	if eventType == nil {
+		return 0, false
+	}
+	if eventType.Kind() != reflect.Pointer {
+		eventType = reflect.PointerTo(eventType)
+	}
+
+	for subscriptionName, versionConstructor := range eventConstructors {
+		for _, constructor := range versionConstructor {
+			if reflect.TypeOf(constructor()) == eventType {
+				return subscriptionName, true
+			}
+		}
+	}
+
+	return 0, false
+}
@@ -0,0 +1,145 @@
+package gokick_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/scorfly/gokick"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestSigner(t *testing.T) (*gokick.WebhookSigner, *gokick.WebhookVerifier) {
+	t.Helper()
+
+	signer, err := gokick.GenerateWebhookSigner()
+	require.NoError(t, err)
+
+	verifier := newTestVerifier(t, gokick.WebhookVerifierOptions{
+		KeyProvider:      signer.KeyProvider(),
+		ReplayProtection: gokick.NewReplayProtection(gokick.ReplayProtectionOptions{}),
+	})
+
+	return signer, verifier
+}
+
+func TestWebhookSignerNewRequest(t *testing.T) {
+	signer, verifier := newTestSigner(t)
+
+	event := &gokick.ChatMessageEvent{MessageID: "message", Content: "hello"}
+	req, err := signer.NewRequest(context.Background(), "https://domain.tld/webhook", event)
+	require.NoError(t, err)
+
+	assert.Equal(t, http.MethodPost, req.Method)
+	assert.Equal(t, "application/json", req.Header.Get("Content-Type"))
+	assert.Equal(t, "chat.message.sent", req.Header.Get("Kick-Event-Type"))
+	assert.Equal(t, "1", req.Header.Get("Kick-Event-Version"))
+	assert.Len(t, req.Header.Get("Kick-Event-Message-Id"), 26)
+	assert.NotEmpty(t, req.Header.Get("Kick-Event-Subscription-Id"))
+	assert.NotEmpty(t, req.Header.Get("Kick-Event-Signature"))
+
+	timestamp, err := time.Parse(time.RFC3339, req.Header.Get("Kick-Event-Message-Timestamp"))
+	require.NoError(t, err)
+	assert.WithinDuration(t, time.Now(), timestamp, time.Minute)
+
+	parsed, err := verifier.GetEventFromRequest(req)
+	require.NoError(t, err)
+	assert.Equal(t, event, parsed)
+}
+
+func TestWebhookSignerNewRequestValue(t *testing.T) {
+	signer, verifier := newTestSigner(t)
+
+	req, err := signer.NewRequest(context.Background(), "https://domain.tld/webhook", gokick.KicksGiftedEvent{})
+	require.NoError(t, err)
+	assert.Equal(t, "kicks.gifted", req.Header.Get("Kick-Event-Type"))
+
+	event, err := verifier.GetEventFromRequest(req)
+	require.NoError(t, err)
+	assert.IsType(t, &gokick.KicksGiftedEvent{}, event)
+}
+
+func TestWebhookSignerNewRequestError(t *testing.T) {
+	signer, _ := newTestSigner(t)
+
+	_, err := signer.NewRequest(context.Background(), "https://domain.tld/webhook", map[string]string{})
+	require.EqualError(t, err, "unsupported event type map[string]string")
+
+	_, err = signer.NewRequest(context.Background(), "https://domain.tld/webhook", nil)
+	require.EqualError(t, err, "unsupported event type <nil>")
+
+	_, err = signer.NewRequest(context.Background(), "://invalid", &gokick.ChannelFollowEvent{})
+	require.EqualError(t, err, `failed to create request: parse "://invalid": missing protocol scheme`)
+}
+
+func TestWebhookSignerNewRawRequest(t *testing.T) {
+	signer, verifier := newTestSigner(t)
+
+	delivery := gokick.WebhookDelivery{
+		SubscriptionName: gokick.SubscriptionNameChannelFollow,
+		Version:          "2",
+		MessageID:        "01JMND5PSxxxxxx",
+		SubscriptionID:   "01JMN13xxxxxx",
+		Timestamp:        time.Now().Add(-time.Minute),
+	}
+
+	req, err := signer.NewRawRequest(context.Background(), "https://domain.tld/webhook", delivery, []byte(`{"custom":true}`))
+	require.NoError(t, err)
+	assert.Equal(t, "2", req.Header.Get("Kick-Event-Version"))
+	assert.Equal(t, "01JMND5PSxxxxxx", req.Header.Get("Kick-Event-Message-Id"))
+	assert.Equal(t, "01JMN13xxxxxx", req.Header.Get("Kick-Event-Subscription-Id"))
+	assert.Equal(t, delivery.Timestamp.UTC().Format(time.RFC3339), req.Header.Get("Kick-Event-Message-Timestamp"))
+
+	event, err := verifier.GetEventFromRequest(req)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"custom": true}, event)
+
+	retry, err := signer.NewRawRequest(context.Background(), "https://domain.tld/webhook", delivery, []byte(`{"custom":true}`))
+	require.NoError(t, err)
+	_, err = verifier.GetEventFromRequest(retry)
+	require.ErrorIs(t, err, gokick.ErrDuplicateEvent)
+}
+
+func TestWebhookSignerPublicKeyPEM(t *testing.T) {
+	signer, _ := newTestSigner(t)
+
+	encoded, err := signer.PublicKeyPEM()
+	require.NoError(t, err)
+
+	publicKey, err := gokick.ParsePublicKey(encoded)
+	require.NoError(t, err)
+	assert.True(t, publicKey.Equal(signer.PublicKey()))
+}
+
+func TestWebhookSignerEventHandler(t *testing.T) {
+	signer, verifier := newTestSigner(t)
+
+	var received *gokick.ChannelFollowEvent
+	handler := gokick.NewEventHandler()
+	handler.SetVerifier(verifier)
+	handler.OnChannelFollow(func(_ context.Context, event *gokick.ChannelFollowEvent, _ gokick.EventMeta) error {
+		received = event
+		return nil
+	})
+
+	event := &gokick.ChannelFollowEvent{Follower: gokick.UserEvent{UserID: 42, Username: "follower"}}
+	req, err := signer.NewRequest(context.Background(), "https://domain.tld/webhook", event)
+	require.NoError(t, err)
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, req)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, event, received)
+
+	other, _ := newTestSigner(t)
+	req, err = other.NewRequest(context.Background(), "https://domain.tld/webhook", event)
+	require.NoError(t, err)
+
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, req)
+	assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+}
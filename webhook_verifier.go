@@ -0,0 +1,166 @@
+package gokick
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// defaultWebhookVerifier backs ValidateEvent, ValidateAndParseEvent, GetEventFromRequest and the EventHandlers
+// without a verifier, with DefaultEventPublicKey and SkipSignatureValidation read on every event.
+var defaultWebhookVerifier = &WebhookVerifier{
+	keyProvider: defaultKeyProvider{},
+	skip:        func() bool { return SkipSignatureValidation },
+}
+
+// WebhookVerifierOptions configures a WebhookVerifier.
+type WebhookVerifierOptions struct {
+	// KeyProvider provides the key verifying the signatures. Defaults to the key of DefaultEventPublicKey at the
+	// time the verifier is created.
+	KeyProvider KeyProvider
+	// SkipSignatureValidation accepts events without verifying their signature. Never set it in production.
+	SkipSignatureValidation bool
+	// ReplayProtection rejects expired and duplicate events. Nil disables it.
+	ReplayProtection *ReplayProtection
+}
+
+// WebhookVerifier verifies and parses webhook events with its own key, skip policy and replay protection,
+// unlike the package functions relying on the DefaultEventPublicKey and SkipSignatureValidation globals. Several
+// verifiers, e.g. one per application, can be used concurrently.
+type WebhookVerifier struct {
+	keyProvider KeyProvider
+	skip        func() bool
+	replay      *ReplayProtection
+}
+
+func NewWebhookVerifier(options WebhookVerifierOptions) (*WebhookVerifier, error) {
+	if options.KeyProvider == nil {
+		publicKey, err := ParsePublicKey([]byte(DefaultEventPublicKey))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse public key: %w", err)
+		}
+		options.KeyProvider = NewStaticKeyProvider(publicKey)
+	}
+
+	skip := options.SkipSignatureValidation
+
+	return &WebhookVerifier{
+		keyProvider: options.KeyProvider,
+		skip:        func() bool { return skip },
+		replay:      options.ReplayProtection,
+	}, nil
+}
+
+// ValidateEvent reports whether the signature of the event is valid and, with a ReplayProtection, whether the
+// event is not expired. It does not record the message ID: duplicates are detected by ValidateAndParseEvent and
+// GetEventFromRequest, which can be called after it.
+func (v *WebhookVerifier) ValidateEvent(header http.Header, body []byte) bool {
+	_, _, signature, messageID, timestamp := webhookRequestMeta(header)
+
+	err := v.verifySignature(context.Background(), signature, messageID, timestamp, string(body))
+	if err != nil {
+		return false
+	}
+
+	return v.checkAge(timestamp) == nil
+}
+
+// ValidateAndParseEvent verifies the event, then decodes it into the type of subscriptionName and version.
+func (v *WebhookVerifier) ValidateAndParseEvent(
+	subscriptionName SubscriptionName,
+	version string,
+	eventSignature string,
+	messageID string,
+	timestamp string,
+	body string,
+) (interface{}, error) {
+	err := v.verifySignature(context.Background(), eventSignature, messageID, timestamp, body)
+	if err != nil {
+		return nil, err
+	}
+
+	err = v.checkReplay(context.Background(), messageID, timestamp)
+	if err != nil {
+		return nil, err
+	}
+
+	event, err := parseEvent(subscriptionName, version, body)
+	if err != nil {
+		v.forget(context.Background(), messageID)
+		return nil, err
+	}
+
+	return event, nil
+}
+
+// GetEventFromRequest verifies the event of a webhook request, then decodes it into its type.
+func (v *WebhookVerifier) GetEventFromRequest(request *http.Request) (interface{}, error) {
+	if request == nil {
+		return nil, errors.New("request cannot be nil")
+	}
+
+	eventName, version, eventSignature, messageID, timestamp := webhookRequestMeta(request.Header)
+
+	subscriptionName, err := NewSubscriptionName(eventName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse subscription name: %w", err)
+	}
+
+	body, err := io.ReadAll(request.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read body: %w", err)
+	}
+
+	trace := ContextTrace(request.Context())
+
+	err = trace.verifyEventSignature(request.Context(), v, eventSignature, messageID, timestamp, string(body))
+	if err != nil {
+		return nil, err
+	}
+
+	err = v.checkReplay(request.Context(), messageID, timestamp)
+	if err != nil {
+		return nil, err
+	}
+
+	event, err := trace.parseEvent(subscriptionName, version, string(body))
+	if err != nil {
+		v.forget(request.Context(), messageID)
+		return nil, err
+	}
+
+	return event, nil
+}
+
+func (v *WebhookVerifier) verifySignature(ctx context.Context, eventSignature, messageID, timestamp, body string) error {
+	if v.skip() {
+		return nil
+	}
+
+	return verifyEventSignature(ctx, v.keyProvider, eventSignature, messageID, timestamp, body)
+}
+
+func (v *WebhookVerifier) checkReplay(ctx context.Context, messageID, timestamp string) error {
+	if v.replay == nil {
+		return nil
+	}
+
+	return v.replay.Check(ctx, messageID, timestamp)
+}
+
+func (v *WebhookVerifier) checkAge(timestamp string) error {
+	if v.replay == nil {
+		return nil
+	}
+
+	return v.replay.checkAge(timestamp)
+}
+
+// forget lets an event that could not be processed be delivered again.
+func (v *WebhookVerifier) forget(ctx context.Context, messageID string) {
+	if v.replay != nil {
+		_ = v.replay.Forget(ctx, messageID)
+	}
+}
@@ -0,0 +1,158 @@
+package gokick_test
+
+import (
+	"io"
+	"testing"
+	"time"
+
+	"github.com/scorfly/gokick"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestVerifier(t *testing.T, options gokick.WebhookVerifierOptions) *gokick.WebhookVerifier {
+	t.Helper()
+
+	verifier, err := gokick.NewWebhookVerifier(options)
+	require.NoError(t, err)
+
+	return verifier
+}
+
+func TestNewWebhookVerifierError(t *testing.T) {
+	previousKey := gokick.DefaultEventPublicKey
+	t.Cleanup(func() { gokick.DefaultEventPublicKey = previousKey })
+
+	gokick.DefaultEventPublicKey = "invalid key"
+
+	_, err := gokick.NewWebhookVerifier(gokick.WebhookVerifierOptions{})
+	require.EqualError(t, err, "failed to parse public key: failed to decode public key")
+}
+
+func TestWebhookVerifier(t *testing.T) {
+	privateKey := generateKey(t)
+	verifier := newTestVerifier(t, gokick.WebhookVerifierOptions{
+		KeyProvider: gokick.NewStaticKeyProvider(&privateKey.PublicKey),
+	})
+
+	t.Run("ValidateEvent", func(t *testing.T) {
+		req := newSignedWebhookRequest(t, privateKey, "{}")
+		assert.True(t, verifier.ValidateEvent(req.Header, []byte("{}")))
+		assert.False(t, verifier.ValidateEvent(req.Header, []byte(`{"tampered":true}`)))
+		assert.False(t, gokick.ValidateEvent(req.Header, []byte("{}")), "the package function uses DefaultEventPublicKey")
+	})
+
+	t.Run("ValidateAndParseEvent", func(t *testing.T) {
+		req := newSignedWebhookRequest(t, privateKey, "{}")
+
+		event, err := verifier.ValidateAndParseEvent(
+			gokick.SubscriptionNameChannelFollow,
+			"1",
+			req.Header.Get("Kick-Event-Signature"),
+			req.Header.Get("Kick-Event-Message-Id"),
+			req.Header.Get("Kick-Event-Message-Timestamp"),
+			"{}",
+		)
+		require.NoError(t, err)
+		assert.IsType(t, &gokick.ChannelFollowEvent{}, event)
+
+		_, err = verifier.ValidateAndParseEvent(
+			gokick.SubscriptionNameChannelFollow,
+			"1",
+			req.Header.Get("Kick-Event-Signature"),
+			"another message ID",
+			req.Header.Get("Kick-Event-Message-Timestamp"),
+			"{}",
+		)
+		require.EqualError(t, err, "failed to verify event validity: failed to verify signature: crypto/rsa: verification error")
+	})
+
+	t.Run("GetEventFromRequest", func(t *testing.T) {
+		event, err := verifier.GetEventFromRequest(newSignedWebhookRequest(t, privateKey, "{}"))
+		require.NoError(t, err)
+		assert.IsType(t, &gokick.ChannelFollowEvent{}, event)
+
+		_, err = verifier.GetEventFromRequest(nil)
+		require.EqualError(t, err, "request cannot be nil")
+	})
+
+	t.Run("ignores SkipSignatureValidation", func(t *testing.T) {
+		skipSignatureValidation(t)
+
+		_, err := verifier.GetEventFromRequest(newSignedWebhookRequest(t, generateKey(t), "{}"))
+		require.EqualError(t, err, "failed to verify event validity: failed to verify signature: crypto/rsa: verification error")
+	})
+}
+
+func TestWebhookVerifierSkipSignatureValidation(t *testing.T) {
+	verifier := newTestVerifier(t, gokick.WebhookVerifierOptions{SkipSignatureValidation: true})
+
+	event, err := verifier.GetEventFromRequest(newWebhookRequest(t, "channel.followed", "1", "{}"))
+	require.NoError(t, err)
+	assert.IsType(t, &gokick.ChannelFollowEvent{}, event)
+
+	_, err = gokick.GetEventFromRequest(newWebhookRequest(t, "channel.followed", "1", "{}"))
+	require.Error(t, err, "the package function still verifies the signature")
+}
+
+func TestWebhookVerifierReplayProtection(t *testing.T) {
+	sentAt, err := time.Parse(time.RFC3339, "2025-02-21T23:23:36Z")
+	require.NoError(t, err)
+
+	now := sentAt.Add(time.Minute)
+	protection := gokick.NewReplayProtection(gokick.ReplayProtectionOptions{MaxAge: 5 * time.Minute})
+	gokick.SetReplayProtectionNow(protection, func() time.Time { return now })
+	verifier := newTestVerifier(t, gokick.WebhookVerifierOptions{
+		SkipSignatureValidation: true,
+		ReplayProtection:        protection,
+	})
+
+	_, err = verifier.GetEventFromRequest(newWebhookRequest(t, "channel.followed", "1", "{}"))
+	require.NoError(t, err)
+
+	_, err = verifier.GetEventFromRequest(newWebhookRequest(t, "channel.followed", "1", "{}"))
+	require.ErrorIs(t, err, gokick.ErrDuplicateEvent)
+
+	now = sentAt.Add(time.Hour)
+	req := newWebhookRequest(t, "channel.followed", "1", "{}")
+	req.Header.Set("Kick-Event-Message-Id", "another message ID")
+	body, err := io.ReadAll(req.Body)
+	require.NoError(t, err)
+	assert.False(t, verifier.ValidateEvent(req.Header, body), "the event is expired for the clock of the replay protection")
+
+	_, err = verifier.ValidateAndParseEvent(gokick.SubscriptionNameChannelFollow, "1", "", "another message ID",
+		"2025-02-21T23:23:36Z", "{}")
+	require.ErrorIs(t, err, gokick.ErrEventExpired)
+}
+
+func TestWebhookVerifierReplayProtectionSeenOnce(t *testing.T) {
+	verifier := newTestVerifier(t, gokick.WebhookVerifierOptions{
+		SkipSignatureValidation: true,
+		ReplayProtection:        gokick.NewReplayProtection(gokick.ReplayProtectionOptions{}),
+	})
+	timestamp := time.Now().Format(time.RFC3339)
+
+	t.Run("ValidateEvent does not mark the event as seen", func(t *testing.T) {
+		req := newWebhookRequest(t, "channel.followed", "1", "{}")
+		req.Header.Set("Kick-Event-Message-Timestamp", timestamp)
+		assert.True(t, verifier.ValidateEvent(req.Header, []byte("{}")))
+		assert.True(t, verifier.ValidateEvent(req.Header, []byte("{}")))
+
+		_, err := verifier.ValidateAndParseEvent(gokick.SubscriptionNameChannelFollow, "1", "", "validated", timestamp, "{}")
+		require.NoError(t, err)
+	})
+
+	t.Run("unparsable event is forgotten", func(t *testing.T) {
+		for range 2 {
+			_, err := verifier.ValidateAndParseEvent(gokick.SubscriptionNameChannelFollow, "1", "", "unparsable", timestamp, "{")
+			require.Error(t, err)
+			require.NotErrorIs(t, err, gokick.ErrDuplicateEvent)
+
+			req := newWebhookRequest(t, "channel.followed", "1", "{")
+			req.Header.Set("Kick-Event-Message-Timestamp", timestamp)
+			_, err = verifier.GetEventFromRequest(req)
+			require.Error(t, err)
+			require.NotErrorIs(t, err, gokick.ErrDuplicateEvent)
+		}
+	})
+}